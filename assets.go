@@ -0,0 +1,66 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package carol
+
+//------------------------------------------------------------------------------
+
+import (
+	"archive/zip"
+	"io/fs"
+	"os"
+
+	"github.com/drakmaniso/carol/internal"
+)
+
+//------------------------------------------------------------------------------
+
+// Mount adds a file system on top of the asset file system: its files take
+// precedence over those of the previously mounted ones. This is used to load
+// the pictures (in the "graphics" directory), the meshes and the configuration
+// file.
+//
+// Mounts must be done before Run. If nothing is mounted, the assets are loaded
+// from the directory of the executable. For example, to use assets embedded in
+// the binary, but allow a "mod" directory to override them:
+//
+//  //go:embed graphics init.json
+//  var assets embed.FS
+//
+//  func main() {
+//  	carol.Mount(assets)
+//  	carol.MountDir("mod")
+//  	...
+//  }
+func Mount(fsys fs.FS) {
+	internal.Assets.Mount(fsys)
+}
+
+// MountDir adds a directory on top of the asset file system.
+func MountDir(dir string) {
+	internal.Assets.Mount(os.DirFS(dir))
+}
+
+// MountZip adds the content of a zip archive on top of the asset file system.
+// The archive stays open for the duration of the program.
+func MountZip(path string) error {
+	z, err := zip.OpenReader(path)
+	if err != nil {
+		return internal.Error(`while opening archive "`+path+`"`, err)
+	}
+	internal.Assets.Mount(z)
+	return nil
+}
+
+// UnmountAll removes all mounted file systems, reverting to the directory of
+// the executable.
+func UnmountAll() {
+	internal.Assets.UnmountAll()
+}
+
+// Assets returns the asset file system.
+func Assets() fs.FS {
+	return internal.Assets
+}
+
+//------------------------------------------------------------------------------
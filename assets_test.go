@@ -0,0 +1,77 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package carol_test
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/drakmaniso/carol"
+)
+
+//------------------------------------------------------------------------------
+
+func TestMount(t *testing.T) {
+	defer carol.UnmountAll()
+
+	carol.Mount(fstest.MapFS{
+		"init.json":          {Data: []byte("base")},
+		"graphics/a.png":     {Data: []byte("a")},
+		"graphics/b.png":     {Data: []byte("b")},
+		"graphics/sub/c.png": {Data: []byte("c")},
+	})
+	carol.Mount(fstest.MapFS{
+		"graphics/b.png": {Data: []byte("mod b")},
+		"graphics/d.png": {Data: []byte("d")},
+	})
+
+	a := carol.Assets()
+
+	for n, want := range map[string]string{
+		"init.json":          "base",
+		"graphics/a.png":     "a",
+		"graphics/b.png":     "mod b",
+		"graphics/d.png":     "d",
+		"graphics/sub/c.png": "c",
+	} {
+		b, err := fs.ReadFile(a, n)
+		if err != nil {
+			t.Errorf("reading %s: %v", n, err)
+			continue
+		}
+		if string(b) != want {
+			t.Errorf("reading %s: got %q, want %q", n, b, want)
+		}
+	}
+
+	var files []string
+	err := fs.WalkDir(a, "graphics", func(p string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			files = append(files, p)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"graphics/a.png", "graphics/b.png", "graphics/d.png", "graphics/sub/c.png"}
+	if len(files) != len(want) {
+		t.Fatalf("walk: got %v, want %v", files, want)
+	}
+	for i := range want {
+		if files[i] != want[i] {
+			t.Errorf("walk: got %v, want %v", files, want)
+			break
+		}
+	}
+
+	_, err = a.Open("missing.png")
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("opening missing file: got %v", err)
+	}
+}
+
+//------------------------------------------------------------------------------
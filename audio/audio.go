@@ -0,0 +1,201 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package audio
+
+//------------------------------------------------------------------------------
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/drakmaniso/carol/internal"
+)
+
+//------------------------------------------------------------------------------
+
+// The loggers of the audio subsystem.
+var (
+	debug   = internal.Logger{System: "audio", Level: internal.DebugLevel}
+	warning = internal.Logger{System: "audio", Level: internal.WarningLevel}
+)
+
+// Errors reported by package audio. They can be tested with errors.Is.
+var (
+	ErrSoundNotFound = errors.New("sound not found")
+	ErrMusicNotFound = errors.New("music not found")
+	ErrFormat        = errors.New("unknown audio format")
+)
+
+var stickyErr internal.StickyError
+
+// Err returns the first unchecked error of package audio, and considers it
+// checked. If Config.CollectErrors is set, it returns all unchecked errors
+// instead.
+func Err() error {
+	return stickyErr.Err()
+}
+
+func setErr(context string, err error) {
+	stickyErr.Set(internal.Error(context, err))
+	warning.Printf("%s", internal.Error(context, err))
+}
+
+//------------------------------------------------------------------------------
+
+// Parameters of the audio device.
+const (
+	defaultRate   = 48000
+	deviceFrames  = 1024
+	streamFrames  = 4096 // size of the chunks decoded for streamed voices
+	maxSampleRate = 192000
+)
+
+// mixer holds the state shared with the audio thread.
+var mixer struct {
+	sync.Mutex
+	rate   int
+	volume float32
+	voices []*Voice
+	silent bool // set up without device: nothing would call Render
+}
+
+func init() {
+	mixer.rate = defaultRate
+	mixer.volume = 1
+	internal.AudioSetup = setupHook
+	internal.AudioQuit = quitHook
+}
+
+func setupHook() error {
+	err := loadAllSounds()
+	if err != nil {
+		return err
+	}
+
+	if internal.Headless || internal.Offscreen {
+		setSilent(true)
+		return nil
+	}
+
+	r, err := internal.AudioOpen(defaultRate, deviceFrames, Render)
+	if err != nil {
+		// The game runs without sound
+		warning.Printf("Unable to open audio device: %s", err)
+		setSilent(true)
+		return nil
+	}
+	mixer.Lock()
+	mixer.rate = r
+	mixer.silent = false
+	mixer.Unlock()
+	debug.Printf("Audio device opened at %d Hz", r)
+	return nil
+}
+
+func quitHook() {
+	internal.AudioClose()
+	mixer.Lock()
+	for _, v := range mixer.voices {
+		v.done = true
+		v.release()
+	}
+	mixer.voices = mixer.voices[:0]
+	mixer.silent = false
+	mixer.Unlock()
+	music.voice = nil
+	music.name = ""
+}
+
+// setSilent changes whether the voices are played. When silent, they are
+// stopped from the start, and never added to the mixer.
+func setSilent(s bool) {
+	mixer.Lock()
+	mixer.silent = s
+	mixer.Unlock()
+}
+
+func isSilent() bool {
+	mixer.Lock()
+	defer mixer.Unlock()
+	return mixer.silent
+}
+
+//------------------------------------------------------------------------------
+
+// SampleRate returns the sample rate of the output.
+func SampleRate() int {
+	mixer.Lock()
+	defer mixer.Unlock()
+	return mixer.rate
+}
+
+// SetSampleRate changes the sample rate used by Render. It is only useful
+// outside of a game loop: when an audio device is open, its rate is used.
+func SetSampleRate(r int) {
+	if r < 1 || r > maxSampleRate {
+		setErr("in SetSampleRate", errors.New("invalid sample rate"))
+		return
+	}
+	mixer.Lock()
+	mixer.rate = r
+	mixer.Unlock()
+}
+
+// SetVolume changes the master volume, applied to all buses.
+func SetVolume(v float32) {
+	mixer.Lock()
+	mixer.volume = v
+	mixer.Unlock()
+}
+
+// Volume returns the master volume.
+func Volume() float32 {
+	mixer.Lock()
+	defer mixer.Unlock()
+	return mixer.volume
+}
+
+//------------------------------------------------------------------------------
+
+// Render mixes all playing voices into out, as interleaved stereo samples (left
+// then right). It advances the playback by len(out)/2 frames.
+//
+// It is called by the audio device; outside of a game loop, it can be called
+// directly to obtain the output.
+func Render(out []float32) {
+	for i := range out {
+		out[i] = 0
+	}
+
+	mixer.Lock()
+	defer mixer.Unlock()
+
+	j := 0
+	for _, v := range mixer.voices {
+		if !v.done {
+			v.mix(out, mixer.volume)
+		}
+		if !v.done {
+			mixer.voices[j] = v
+			j++
+		} else {
+			v.release()
+		}
+	}
+	for i := j; i < len(mixer.voices); i++ {
+		mixer.voices[i] = nil
+	}
+	mixer.voices = mixer.voices[:j]
+
+	for i, s := range out {
+		switch {
+		case s > 1:
+			out[i] = 1
+		case s < -1:
+			out[i] = -1
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
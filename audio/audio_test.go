@@ -0,0 +1,487 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package audio
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/drakmaniso/carol/internal"
+)
+
+//------------------------------------------------------------------------------
+
+func TestIMDCT(t *testing.T) {
+	for _, n := range []int{64, 512} {
+		in := make([]float32, n/2)
+		for k := range in {
+			in[k] = float32(math.Sin(float64(k*k)) + 0.1*float64(k%7))
+		}
+		out := make([]float32, n)
+		newIMDCT(n).inverse(in, out)
+
+		for i := range out {
+			var y float64
+			for k, x := range in {
+				y += float64(x) * math.Cos(2*math.Pi/float64(n)*(float64(i)+0.5+float64(n)/4)*(float64(k)+0.5))
+			}
+			if math.Abs(float64(out[i])-y) > 1e-3 {
+				t.Fatalf("n=%d: out[%d] = %g, expected %g", n, i, out[i], y)
+			}
+		}
+	}
+}
+
+func TestCodebook(t *testing.T) {
+	// Example from the Vorbis specification
+	lengths := []int{2, 4, 4, 4, 4, 2, 3, 3}
+	codewords := []string{"00", "0100", "0101", "0110", "0111", "10", "110", "111"}
+
+	c := codebook{tree: []int32{0, 0}, full: []bool{false}}
+	for e, l := range lengths {
+		if !c.insert(0, 1, l, e) {
+			t.Fatalf("cannot insert entry %d", e)
+		}
+	}
+	for e, w := range codewords {
+		var data [1]byte
+		for i, b := range w {
+			if b == '1' {
+				data[0] |= 1 << uint(i)
+			}
+		}
+		if got := c.decode(&bitReader{data: data[:]}); got != e {
+			t.Errorf("codeword %s: got entry %d, expected %d", w, got, e)
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestWAV(t *testing.T) {
+	in := []int16{0, 16384, -16384, 32767, -32768, 8192}
+	d, err := newDecoder(wavFile(2, 22050, in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, r := d.format()
+	if c != 2 || r != 22050 {
+		t.Fatalf("format: %d channels at %d Hz", c, r)
+	}
+	s, err := decodeAll(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s) != len(in) {
+		t.Fatalf("decoded %d samples, expected %d", len(s), len(in))
+	}
+	for i := range in {
+		if s[i] != float32(in[i])/32768 {
+			t.Errorf("sample %d: %g", i, s[i])
+		}
+	}
+}
+
+// wavFile returns a 16-bit PCM WAV file.
+func wavFile(channels, rate int, samples []int16) []byte {
+	b := make([]byte, 44+2*len(samples))
+	le := binary.LittleEndian
+	copy(b[0:], "RIFF")
+	le.PutUint32(b[4:], uint32(len(b)-8))
+	copy(b[8:], "WAVEfmt ")
+	le.PutUint32(b[16:], 16)
+	le.PutUint16(b[20:], 1)
+	le.PutUint16(b[22:], uint16(channels))
+	le.PutUint32(b[24:], uint32(rate))
+	le.PutUint32(b[28:], uint32(rate*channels*2))
+	le.PutUint16(b[32:], uint16(channels*2))
+	le.PutUint16(b[34:], 16)
+	copy(b[36:], "data")
+	le.PutUint32(b[40:], uint32(2*len(samples)))
+	for i, s := range samples {
+		le.PutUint16(b[44+2*i:], uint16(s))
+	}
+	return b
+}
+
+//------------------------------------------------------------------------------
+
+func TestVorbis(t *testing.T) {
+	// Spectra of the audio packets; the values are 0 or 1
+	var spectra [5][32]float32
+	for p := range spectra {
+		for k := range spectra[p] {
+			if k/8 != p%4 && (k*7+p*3)%5 < 2 {
+				spectra[p][k] = 1
+			}
+		}
+	}
+	const frames = 4*32 - 5 // the last packet is partially used
+
+	// Expected output, with the textbook formulas of the inverse MDCT and the
+	// Vorbis window, and overlap-add of the successive blocks.
+	const n = 64
+	var blocks [len(spectra)][n]float64
+	for p := range spectra {
+		for i := 0; i < n; i++ {
+			var y float64
+			for k, x := range spectra[p] {
+				y += float64(x) * math.Cos(2*math.Pi/n*(float64(i)+0.5+n/4)*(float64(k)+0.5))
+			}
+			s := math.Sin(math.Pi * (float64(i) + 0.5) / n)
+			blocks[p][i] = y * math.Sin(math.Pi/2*s*s)
+		}
+	}
+	want := make([]float64, frames)
+	for i := range want {
+		p, j := i/32+1, i%32
+		want[i] = blocks[p-1][32+j] + blocks[p][j]
+	}
+
+	d, err := newDecoder(vorbisFile(spectra[:], frames))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, r := d.format()
+	if c != 1 || r != 8000 {
+		t.Fatalf("format: %d channels at %d Hz", c, r)
+	}
+	for pass := 0; pass < 2; pass++ {
+		s, err := decodeAll(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(s) != len(want) {
+			t.Fatalf("pass %d: decoded %d samples, expected %d", pass, len(s), len(want))
+		}
+		for i := range want {
+			if math.Abs(float64(s[i])-want[i]) > 1e-4 {
+				t.Fatalf("pass %d: sample %d = %g, expected %g", pass, i, s[i], want[i])
+			}
+		}
+		err = d.rewind()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCorruptCodebook(t *testing.T) {
+	tests := []struct {
+		entries, dims, lookup int
+	}{
+		{2, 60000, 2},      // table larger than the packet
+		{1024, 60000, 1},   // table too large
+		{1024, 60000, 2},   // both
+		{1 << 10, 2048, 1}, // few values, but 2M vector components
+	}
+	for _, tt := range tests {
+		var w bitWriter
+		w.write(0x564342, 24)
+		w.write(uint32(tt.dims), 16)
+		w.write(uint32(tt.entries), 24)
+		w.write(1, 1) // ordered, all of the same length
+		w.write(uint32(ilog(tt.entries-1)-1), 5)
+		w.write(uint32(tt.entries), ilog(tt.entries))
+		w.write(uint32(tt.lookup), 4)
+		w.write(0, 32)
+		w.write(788<<21|1, 32)
+		w.write(31, 4) // 32 bits per multiplicand
+		w.write(0, 1)
+		w.write(0, 32)
+
+		var d vorbisDecoder
+		_, err := d.readCodebook(&bitReader{data: w.data})
+		if err != errVorbisSetup {
+			t.Errorf("%d entries of %d dimensions: got %v", tt.entries, tt.dims, err)
+		}
+	}
+}
+
+func TestSafely(t *testing.T) {
+	err := safely(func() error {
+		var s []float32
+		s[1] = 0
+		return nil
+	})
+	if err == nil {
+		t.Errorf("panic not turned into an error")
+	}
+}
+
+// vorbisFile returns a mono Ogg Vorbis file with one short block (64 samples)
+// per spectrum, and the given length in frames. The setup uses a flat floor,
+// so the residue values are the MDCT coefficients.
+func vorbisFile(spectra [][32]float32, frames int) []byte {
+	var id, comment, setup bitWriter
+	id.bytes(1, 'v', 'o', 'r', 'b', 'i', 's')
+	id.write(0, 32)    // version
+	id.write(1, 8)     // channels
+	id.write(8000, 32) // rate
+	id.write(0, 32)
+	id.write(0, 32)
+	id.write(0, 32)
+	id.write(6, 4) // block sizes: 64 and 64
+	id.write(6, 4)
+	id.write(1, 1)
+
+	comment.bytes(3, 'v', 'o', 'r', 'b', 'i', 's')
+	comment.write(0, 32) // vendor
+	comment.write(0, 32) // user comments
+	comment.write(1, 1)
+
+	setup.bytes(5, 'v', 'o', 'r', 'b', 'i', 's')
+	setup.write(0, 8) // one codebook: values 0 and 1, with codewords "0" and "1"
+	setup.write(0x564342, 24)
+	setup.write(1, 16)
+	setup.write(2, 24)
+	setup.write(0, 1) // unordered
+	setup.write(0, 1) // not sparse
+	setup.write(0, 5)
+	setup.write(0, 5)
+	setup.write(1, 4)          // lookup type 1
+	setup.write(0, 32)         // min 0
+	setup.write(788<<21|1, 32) // delta 1
+	setup.write(0, 4)          // 1 bit per multiplicand
+	setup.write(0, 1)
+	setup.write(0, 1)
+	setup.write(1, 1)
+	setup.write(0, 6) // time domain transforms
+	setup.write(0, 16)
+	setup.write(0, 6) // one floor of type 1, without partitions
+	setup.write(1, 16)
+	setup.write(0, 5)
+	setup.write(0, 2) // multiplier 1
+	setup.write(5, 4) // x of the last point: 32
+	setup.write(0, 6) // one residue of type 1, in partitions of 8
+	setup.write(1, 16)
+	setup.write(0, 24)
+	setup.write(32, 24)
+	setup.write(7, 24)
+	setup.write(1, 6) // two classes: zero, or values of the codebook
+	setup.write(0, 8)
+	setup.write(0, 3)
+	setup.write(0, 1)
+	setup.write(1, 3)
+	setup.write(0, 1)
+	setup.write(0, 8)
+	setup.write(0, 6) // one mapping
+	setup.write(0, 16)
+	setup.write(0, 1)
+	setup.write(0, 1)
+	setup.write(0, 2)
+	setup.write(0, 8)
+	setup.write(0, 8)
+	setup.write(0, 8)
+	setup.write(0, 6) // one short mode
+	setup.write(0, 1)
+	setup.write(0, 16)
+	setup.write(0, 16)
+	setup.write(0, 8)
+	setup.write(1, 1)
+
+	var audio [][]byte
+	for _, s := range spectra {
+		var a bitWriter
+		a.write(0, 1)   // audio packet
+		a.write(1, 1)   // floor used
+		a.write(255, 8) // flat at 0 dB
+		a.write(255, 8)
+		for p := 0; p < 4; p++ {
+			v := s[8*p : 8*p+8]
+			var class uint32
+			for _, x := range v {
+				if x != 0 {
+					class = 1
+				}
+			}
+			a.write(class, 1)
+			if class == 1 {
+				for _, x := range v {
+					a.write(uint32(x), 1)
+				}
+			}
+		}
+		audio = append(audio, a.data)
+	}
+
+	f := oggPage(0x02, 0, 0, id.data)
+	f = append(f, oggPage(0, 0, 1, comment.data, setup.data)...)
+	return append(f, oggPage(0x04, int64(frames), 2, audio...)...)
+}
+
+// oggPage returns a page containing the packets.
+func oggPage(flags byte, granule int64, seq uint32, packets ...[]byte) []byte {
+	var lacing, body []byte
+	for _, p := range packets {
+		n := len(p)
+		for ; n >= 255; n -= 255 {
+			lacing = append(lacing, 255)
+		}
+		lacing = append(lacing, byte(n))
+		body = append(body, p...)
+	}
+	return oggRawPage(flags, granule, seq, lacing, body)
+}
+
+// oggRawPage returns a page with the given segments.
+func oggRawPage(flags byte, granule int64, seq uint32, lacing, body []byte) []byte {
+	h := make([]byte, 27, 27+len(lacing)+len(body))
+	copy(h, "OggS")
+	h[5] = flags
+	le := binary.LittleEndian
+	le.PutUint64(h[6:], uint64(granule))
+	le.PutUint32(h[14:], 42)
+	le.PutUint32(h[18:], seq)
+	h[26] = byte(len(lacing))
+	h = append(append(h, lacing...), body...)
+	le.PutUint32(h[22:], oggCRC(h))
+	return h
+}
+
+// A bitWriter packs fields least significant bit first, like a Vorbis
+// packet.
+type bitWriter struct {
+	data []byte
+	pos  int
+}
+
+func (w *bitWriter) write(v uint32, n int) {
+	for i := 0; i < n; i++ {
+		if w.pos%8 == 0 {
+			w.data = append(w.data, 0)
+		}
+		w.data[w.pos/8] |= byte(v>>uint(i)&1) << uint(w.pos%8)
+		w.pos++
+	}
+}
+
+func (w *bitWriter) bytes(b ...byte) {
+	for _, c := range b {
+		w.write(uint32(c), 8)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestRender(t *testing.T) {
+	SetSampleRate(1000)
+	defer SetSampleRate(defaultRate)
+	s := NewSound(1, 1000, []float32{0.5, 0.5, 0.5, 0.5})
+	out := make([]float32, 2*8)
+
+	v := s.Play()
+	v.SetPan(1)
+	SFX.SetVolume(0.5)
+	Render(out)
+	SFX.SetVolume(1)
+	expect(t, "pan", out, []float32{0, 0.25, 0, 0.25, 0, 0.25, 0, 0.25, 0, 0, 0, 0, 0, 0, 0, 0})
+	if v.Playing() {
+		t.Errorf("voice still playing after the end of the sound")
+	}
+
+	v = s.Play()
+	v.SetPitch(2)
+	v.SetLoop(true)
+	Render(out)
+	v.Stop()
+	expect(t, "loop", out, []float32{0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5})
+
+	v = s.Play()
+	v.SetLoop(true)
+	v.FadeOut(0.004)
+	Render(out)
+	expect(t, "fade", out, []float32{0.375, 0.375, 0.25, 0.25, 0.125, 0.125, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0})
+	if v.Playing() {
+		t.Errorf("voice still playing after fading out")
+	}
+}
+
+func TestStream(t *testing.T) {
+	SetSampleRate(1000)
+	defer SetSampleRate(defaultRate)
+	d, err := newDecoder(wavFile(1, 1000, []int16{8192, 16384, -8192}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := newVoice(Music)
+	v.stream = newStream(d, true)
+	v.channels, v.rate = d.format()
+	v.chunk = make([]float32, streamFrames+1)
+	v.loop = true
+	v.start()
+	defer v.Stop()
+
+	// Wait for the decoder to be ahead
+	for len(v.stream.chunks) < streamChunks {
+		time.Sleep(time.Millisecond)
+	}
+
+	out := make([]float32, 2*7)
+	Render(out)
+	expect(t, "stream", out, []float32{0.25, 0.25, 0.5, 0.5, -0.25, -0.25, 0.25, 0.25, 0.5, 0.5, -0.25, -0.25, 0.25, 0.25})
+}
+
+func TestSilent(t *testing.T) {
+	quitHook() // Remove the voices of the other tests
+	internal.Headless = true
+	defer func() { internal.Headless = false }()
+	err := setupHook()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer quitHook()
+	musicFiles["test"] = "music/test.wav"
+	defer delete(musicFiles, "test")
+
+	v := NewSound(1, 1000, []float32{0.5, 0.5}).Play()
+	m := PlayMusic("test", 1)
+	if v.Playing() || m.Playing() {
+		t.Errorf("voices playing without audio device")
+	}
+	mixer.Lock()
+	n := len(mixer.voices)
+	mixer.Unlock()
+	if n != 0 {
+		t.Errorf("%d voices in the mixer, expected none", n)
+	}
+	if Err() != nil {
+		t.Errorf("unexpected error: %s", Err())
+	}
+}
+
+func TestQuitMusic(t *testing.T) {
+	SetSampleRate(1000)
+	defer SetSampleRate(defaultRate)
+	musicFiles["test"] = "music/test.wav"
+	defer delete(musicFiles, "test")
+	internal.Assets.Mount(fstest.MapFS{
+		"music/test.wav": {Data: wavFile(1, 1000, []int16{8192, 16384})},
+	})
+	defer internal.Assets.UnmountAll()
+
+	PlayMusic("test", 0)
+	if CurrentMusic() != "test" {
+		t.Fatalf("got music %q", CurrentMusic())
+	}
+	quitHook()
+	if CurrentMusic() != "" {
+		t.Errorf("got music %q after quit", CurrentMusic())
+	}
+}
+
+func expect(t *testing.T, name string, got, want []float32) {
+	t.Helper()
+	for i := range want {
+		if math.Abs(float64(got[i]-want[i])) > 1e-6 {
+			t.Errorf("%s: got %v, expected %v", name, got, want)
+			return
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,83 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package audio
+
+//------------------------------------------------------------------------------
+
+// A Bus mixes a group of voices, with a common volume.
+type Bus struct {
+	name   string
+	volume float32
+}
+
+var buses = map[string]*Bus{}
+
+// The default buses.
+var (
+	// SFX is used by Sound.Play.
+	SFX = GetBus("sfx")
+	// Music is used by PlayMusic.
+	Music = GetBus("music")
+)
+
+//------------------------------------------------------------------------------
+
+// GetBus returns the bus associated with a name, creating it if necessary.
+func GetBus(name string) *Bus {
+	mixer.Lock()
+	defer mixer.Unlock()
+	b, ok := buses[name]
+	if !ok {
+		b = &Bus{name: name, volume: 1}
+		buses[name] = b
+	}
+	return b
+}
+
+// Name returns the name of the bus.
+func (b *Bus) Name() string {
+	return b.name
+}
+
+// SetVolume changes the volume of all the voices of the bus.
+func (b *Bus) SetVolume(v float32) {
+	mixer.Lock()
+	b.volume = v
+	mixer.Unlock()
+}
+
+// Volume returns the volume of the bus.
+func (b *Bus) Volume() float32 {
+	mixer.Lock()
+	defer mixer.Unlock()
+	return b.volume
+}
+
+// Stop stops all the voices of the bus.
+func (b *Bus) Stop() {
+	mixer.Lock()
+	for _, v := range mixer.voices {
+		if v.bus == b {
+			v.done = true
+		}
+	}
+	mixer.Unlock()
+}
+
+//------------------------------------------------------------------------------
+
+// Play starts playing a sound on the bus.
+func (b *Bus) Play(s *Sound) *Voice {
+	v := newVoice(b)
+	if s == nil || len(s.samples) == 0 || isSilent() {
+		v.done = true
+		return v
+	}
+	v.sound = s
+	v.channels, v.rate = s.channels, s.rate
+	v.buf = s.samples
+	return v.start()
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,83 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package audio
+
+//------------------------------------------------------------------------------
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+//------------------------------------------------------------------------------
+
+// A decoder produces the samples of an audio file.
+type decoder interface {
+	// format returns the number of channels and the sample rate.
+	format() (channels, rate int)
+	// read decodes interleaved samples into p, and returns the number of
+	// frames decoded. It returns 0 at the end of the stream.
+	read(p []float32) (int, error)
+	// rewind restarts the decoding from the beginning.
+	rewind() error
+}
+
+// newDecoder returns a decoder for the content of an audio file, in any of the
+// supported formats.
+func newDecoder(data []byte) (decoder, error) {
+	var d decoder
+	var err error
+	switch {
+	case len(data) >= 12 && bytes.Equal(data[:4], []byte("RIFF")) && bytes.Equal(data[8:12], []byte("WAVE")):
+		d, err = newWAVDecoder(data)
+	case bytes.HasPrefix(data, []byte("OggS")):
+		d, err = newVorbisDecoder(data)
+	default:
+		return nil, ErrFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c, r := d.format()
+	if c != 1 && c != 2 {
+		return nil, errors.New("unsupported number of channels: " + strconv.Itoa(c))
+	}
+	if r < 1 || r > maxSampleRate {
+		return nil, errors.New("unsupported sample rate: " + strconv.Itoa(r))
+	}
+	return d, nil
+}
+
+// safely calls f, and turns a panic of the decoder (e.g. on a corrupt file)
+// into an error.
+func safely(f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("decoder failure: %v", r)
+		}
+	}()
+	return f()
+}
+
+// decodeAll decodes a whole stream.
+func decodeAll(d decoder) ([]float32, error) {
+	c, _ := d.format()
+	var s []float32
+	b := make([]float32, streamFrames*c)
+	for {
+		n, err := d.read(b)
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			return s, nil
+		}
+		s = append(s, b[:n*c]...)
+	}
+}
+
+//------------------------------------------------------------------------------
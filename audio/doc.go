@@ -0,0 +1,21 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+/*
+Package audio provides sound effects and music, mixed in software.
+
+The sounds are loaded at setup from the "sounds" directory of the assets, and
+identified by their path without extension (e.g. "sounds/ui/click.wav" is
+named "ui/click"). They are fully decoded in memory. Music tracks, in the
+"music" directory, are decoded while they play, and can be cross-faded. The
+supported formats are WAV (integer or float PCM) and Ogg Vorbis.
+
+Each sound played is a Voice, with its own volume, pan, pitch and looping. All
+voices are mixed on a Bus, and each bus has its own volume: by default the
+sound effects go to the SFX bus, and the music to the Music bus.
+
+The mixer runs in the audio thread of SDL. In simulations (see carol.Simulate),
+or if the device can't be opened, nothing is played: the voices are stopped
+from the start. Outside of a game loop, the output can be obtained with Render.
+*/
+package audio
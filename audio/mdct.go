@@ -0,0 +1,98 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package audio
+
+//------------------------------------------------------------------------------
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+//------------------------------------------------------------------------------
+
+// An imdct computes the inverse MDCT of a fixed size, via a complex FFT of a
+// quarter of that size.
+type imdct struct {
+	n       int
+	twiddle []complex128 // applied before and after the FFT
+	roots   []complex128 // of the FFT
+	bitrev  []int
+	z       []complex128
+	u       []float64
+}
+
+func newIMDCT(n int) *imdct {
+	m := &imdct{
+		n:       n,
+		twiddle: make([]complex128, n/4),
+		roots:   make([]complex128, n/8),
+		bitrev:  make([]int, n/4),
+		z:       make([]complex128, n/4),
+		u:       make([]float64, n/2),
+	}
+	h := float64(n / 2)
+	for k := range m.twiddle {
+		m.twiddle[k] = cmplx.Exp(complex(0, -math.Pi*(float64(k)+0.125)/h))
+	}
+	q := n / 4
+	for k := range m.roots {
+		m.roots[k] = cmplx.Exp(complex(0, -2*math.Pi*float64(k)/float64(q)))
+	}
+	bits := ilog(q - 1)
+	for i := range m.bitrev {
+		r := 0
+		for b := 0; b < bits; b++ {
+			r |= (i >> uint(b) & 1) << uint(bits-1-b)
+		}
+		m.bitrev[i] = r
+	}
+	return m
+}
+
+// inverse computes the n output samples from the n/2 coefficients of in.
+func (m *imdct) inverse(in []float32, out []float32) {
+	n, h, q := m.n, m.n/2, m.n/4
+
+	// DCT-IV of size n/2, with a complex FFT of size n/4.
+	for k := 0; k < q; k++ {
+		m.z[m.bitrev[k]] = complex(float64(in[2*k]), float64(in[h-1-2*k])) * m.twiddle[k]
+	}
+	m.fft()
+	for k := 0; k < q; k++ {
+		y := m.z[k] * m.twiddle[k]
+		m.u[2*k] = real(y)
+		m.u[h-1-2*k] = -imag(y)
+	}
+
+	// Unfolding into the full block.
+	for i := 0; i < q; i++ {
+		out[i] = float32(m.u[i+q])
+	}
+	for i := q; i < 3*q; i++ {
+		out[i] = float32(-m.u[3*q-1-i])
+	}
+	for i := 3 * q; i < n; i++ {
+		out[i] = float32(-m.u[i-3*q])
+	}
+}
+
+// fft computes in place the forward transform of z, which must be in
+// bit-reversed order.
+func (m *imdct) fft() {
+	q := len(m.z)
+	for size := 2; size <= q; size *= 2 {
+		half, stride := size/2, q/size
+		for start := 0; start < q; start += size {
+			for j := 0; j < half; j++ {
+				a, b := start+j, start+j+half
+				t := m.z[b] * m.roots[j*stride]
+				m.z[b] = m.z[a] - t
+				m.z[a] += t
+			}
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
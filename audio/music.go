@@ -0,0 +1,93 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package audio
+
+//------------------------------------------------------------------------------
+
+import (
+	"fmt"
+	"io/fs"
+
+	"github.com/drakmaniso/carol/internal"
+)
+
+//------------------------------------------------------------------------------
+
+var music struct {
+	voice *Voice
+	name  string
+}
+
+//------------------------------------------------------------------------------
+
+// PlayMusic starts a music track from the "music" directory, looping, on the
+// Music bus. The track is decoded while it plays.
+//
+// If another track is playing, it fades out while the new one fades in, over a
+// duration in seconds.
+func PlayMusic(name string, fade float64) *Voice {
+	p, ok := musicFiles[name]
+	if !ok {
+		setErr("in PlayMusic", fmt.Errorf("%w: %q", ErrMusicNotFound, name))
+		return &Voice{done: true}
+	}
+	if isSilent() {
+		StopMusic(fade)
+		return &Voice{done: true}
+	}
+	b, err := fs.ReadFile(internal.Assets, p)
+	if err != nil {
+		setErr("in PlayMusic", err)
+		return &Voice{done: true}
+	}
+	var d decoder
+	err = safely(func() error {
+		var err error
+		d, err = newDecoder(b)
+		return err
+	})
+	if err != nil {
+		setErr(`in PlayMusic, while decoding "`+p+`"`, err)
+		return &Voice{done: true}
+	}
+
+	StopMusic(fade)
+
+	v := newVoice(Music)
+	v.stream = newStream(d, true)
+	v.channels, v.rate = d.format()
+	v.chunk = make([]float32, (streamFrames+1)*v.channels)
+	v.loop = true
+	if fade > 0 {
+		v.fade = 0
+		mixer.Lock()
+		v.setFade(1, fade, false)
+		mixer.Unlock()
+	}
+	music.voice = v.start()
+	music.name = name
+	return v
+}
+
+// StopMusic fades out the current music track over a duration in seconds.
+func StopMusic(fade float64) {
+	if music.voice == nil {
+		return
+	}
+	music.voice.FadeOut(fade)
+	music.voice = nil
+	music.name = ""
+}
+
+// CurrentMusic returns the name of the music track playing, or the empty
+// string.
+func CurrentMusic() string {
+	if music.voice != nil && !music.voice.Playing() {
+		music.voice = nil
+		music.name = ""
+	}
+	return music.name
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,149 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package audio
+
+//------------------------------------------------------------------------------
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+//------------------------------------------------------------------------------
+
+// An oggReader extracts the packets of the first logical stream of an Ogg
+// file, stored in memory.
+type oggReader struct {
+	data    []byte
+	pos     int // offset of the next page
+	serial  uint32
+	started bool
+
+	lacing []byte // of the current page
+	body   []byte // remaining data of the current page
+	packet []byte
+
+	// last is the granule position of the last page of the stream (i.e. the
+	// total number of frames), or -1 if not reached yet.
+	last int64
+}
+
+var oggCapture = []byte("OggS")
+
+func newOggReader(data []byte) *oggReader {
+	return &oggReader{data: data, last: -1}
+}
+
+// rewind restarts the reading from the first page.
+func (r *oggReader) rewind() {
+	r.pos = 0
+	r.lacing, r.body = nil, nil
+	r.last = -1
+}
+
+// next returns the next packet, or nil at the end of the stream. The packet is
+// only valid until the next call.
+func (r *oggReader) next() ([]byte, error) {
+	r.packet = r.packet[:0]
+	for {
+		for len(r.lacing) == 0 {
+			ok, err := r.nextPage()
+			if err != nil || !ok {
+				return nil, err
+			}
+		}
+		n := int(r.lacing[0])
+		r.lacing = r.lacing[1:]
+		if n > len(r.body) {
+			return nil, errors.New("invalid Ogg page")
+		}
+		r.packet = append(r.packet, r.body[:n]...)
+		r.body = r.body[n:]
+		if n < 255 {
+			return r.packet, nil
+		}
+	}
+}
+
+// nextPage reads the next page of the stream. It returns false at the end of
+// the file.
+func (r *oggReader) nextPage() (bool, error) {
+	for {
+		i := bytes.Index(r.data[r.pos:], oggCapture)
+		if i < 0 || r.pos+i+27 > len(r.data) {
+			return false, nil
+		}
+		p := r.data[r.pos+i:]
+		r.pos += i + 1 // In case of invalid page, resync after the capture
+
+		if p[4] != 0 {
+			continue
+		}
+		flags := p[5]
+		granule := int64(binary.LittleEndian.Uint64(p[6:14]))
+		serial := binary.LittleEndian.Uint32(p[14:18])
+		crc := binary.LittleEndian.Uint32(p[22:26])
+		n := int(p[26])
+		if 27+n > len(p) {
+			return false, nil
+		}
+		lacing := p[27 : 27+n]
+		size := 27 + n
+		for _, l := range lacing {
+			size += int(l)
+		}
+		if size > len(p) {
+			return false, nil
+		}
+		if oggCRC(p[:size]) != crc {
+			continue
+		}
+		r.pos += size - 1
+
+		if !r.started {
+			r.started = true
+			r.serial = serial
+		} else if serial != r.serial {
+			continue
+		}
+		if flags&0x04 != 0 {
+			r.last = granule
+		}
+		r.lacing = lacing
+		r.body = p[27+n : size]
+		return true, nil
+	}
+}
+
+//------------------------------------------------------------------------------
+
+var oggCRCTable = func() (t [256]uint32) {
+	for i := range t {
+		r := uint32(i) << 24
+		for j := 0; j < 8; j++ {
+			if r&0x80000000 != 0 {
+				r = r<<1 ^ 0x04c11db7
+			} else {
+				r <<= 1
+			}
+		}
+		t[i] = r
+	}
+	return t
+}()
+
+// oggCRC computes the checksum of a page (ignoring the checksum field).
+func oggCRC(page []byte) uint32 {
+	var c uint32
+	for i, b := range page {
+		if i >= 22 && i < 26 {
+			b = 0
+		}
+		c = c<<8 ^ oggCRCTable[byte(c>>24)^b]
+	}
+	return c
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,175 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package audio
+
+//------------------------------------------------------------------------------
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/drakmaniso/carol/internal"
+)
+
+//------------------------------------------------------------------------------
+
+// A Sound is an audio clip, decoded in memory.
+type Sound struct {
+	name     string
+	channels int
+	rate     int
+	samples  []float32 // interleaved
+}
+
+// Directories of the sound effects and music tracks, in the asset file system.
+const (
+	soundsPath = "sounds"
+	musicPath  = "music"
+)
+
+var sounds = map[string]*Sound{}
+
+// musicFiles maps the names of the music tracks to their paths.
+var musicFiles = map[string]string{}
+
+//------------------------------------------------------------------------------
+
+// NewSound creates a sound from interleaved samples, with one or two channels.
+func NewSound(channels, rate int, samples []float32) *Sound {
+	if channels != 1 && channels != 2 {
+		setErr("in NewSound", errors.New("sounds must have one or two channels"))
+		channels = 1
+	}
+	if rate < 1 || rate > maxSampleRate {
+		setErr("in NewSound", errors.New("invalid sample rate"))
+		rate = defaultRate
+	}
+	return &Sound{
+		channels: channels,
+		rate:     rate,
+		samples:  samples[:len(samples)/channels*channels],
+	}
+}
+
+// LoadSound loads and decodes a sound file from the asset file system.
+func LoadSound(path string) (*Sound, error) {
+	b, err := fs.ReadFile(internal.Assets, path)
+	if err != nil {
+		return nil, internal.Error(`while reading sound "`+path+`"`, err)
+	}
+	var d decoder
+	var s []float32
+	err = safely(func() error {
+		var err error
+		d, err = newDecoder(b)
+		if err != nil {
+			return err
+		}
+		s, err = decodeAll(d)
+		return err
+	})
+	if err != nil {
+		return nil, internal.Error(`while decoding sound "`+path+`"`, err)
+	}
+	c, r := d.format()
+	return &Sound{name: path, channels: c, rate: r, samples: s}, nil
+}
+
+// GetSound returns the sound associated with a name. If there isn't any, nil
+// is returned (which plays nothing), and a sticky error is set.
+func GetSound(name string) *Sound {
+	s, ok := sounds[name]
+	if !ok {
+		setErr("in GetSound", fmt.Errorf("%w: %q", ErrSoundNotFound, name))
+	}
+	return s
+}
+
+//------------------------------------------------------------------------------
+
+// Name returns the name of the sound.
+func (s *Sound) Name() string {
+	return s.name
+}
+
+// Channels returns the number of channels of the sound.
+func (s *Sound) Channels() int {
+	return s.channels
+}
+
+// SampleRate returns the sample rate of the sound.
+func (s *Sound) SampleRate() int {
+	return s.rate
+}
+
+// Duration returns the length of the sound, in seconds.
+func (s *Sound) Duration() float64 {
+	return float64(len(s.samples)/s.channels) / float64(s.rate)
+}
+
+// Play starts playing the sound on the SFX bus.
+func (s *Sound) Play() *Voice {
+	return SFX.Play(s)
+}
+
+//------------------------------------------------------------------------------
+
+// loadAllSounds decodes the sounds and lists the music tracks found in the
+// asset file system.
+func loadAllSounds() error {
+	err := fs.WalkDir(internal.Assets, soundsPath, func(p string, d fs.DirEntry, err error) error {
+		n, ok := audioFile(p, d, err, soundsPath)
+		if !ok {
+			return err
+		}
+		s, err := LoadSound(p)
+		if err != nil {
+			return err
+		}
+		s.name = n
+		sounds[n] = s
+		return nil
+	})
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return internal.Error("while loading sounds", err)
+	}
+
+	err = fs.WalkDir(internal.Assets, musicPath, func(p string, d fs.DirEntry, err error) error {
+		n, ok := audioFile(p, d, err, musicPath)
+		if ok {
+			musicFiles[n] = p
+		}
+		return err
+	})
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return internal.Error("while scanning music", err)
+	}
+
+	debug.Printf("Loaded %d sounds, found %d music tracks", len(sounds), len(musicFiles))
+	return nil
+}
+
+// audioFile returns the name corresponding to the path of a supported audio
+// file.
+func audioFile(p string, d fs.DirEntry, err error, dir string) (string, bool) {
+	if err != nil || d.IsDir() {
+		return "", false
+	}
+	switch strings.ToLower(path.Ext(p)) {
+	case ".wav", ".ogg":
+	default:
+		return "", false
+	}
+	n := strings.TrimPrefix(p, dir+"/")
+	return strings.TrimSuffix(n, path.Ext(n)), true
+}
+
+//------------------------------------------------------------------------------
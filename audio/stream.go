@@ -0,0 +1,97 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package audio
+
+//------------------------------------------------------------------------------
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+//------------------------------------------------------------------------------
+
+// streamChunks is the number of chunks decoded ahead for each stream.
+const streamChunks = 4
+
+// A stream decodes a file in its own goroutine, a few chunks ahead of the
+// mixer, so that the audio thread never waits for the decoder.
+type stream struct {
+	chunks chan []float32 // decoded, closed at the end of the stream
+	free   chan []float32 // to be reused by the decoder
+	quit   chan struct{}
+	loop   int32 // accessed atomically
+}
+
+// newStream starts decoding d.
+func newStream(d decoder, loop bool) *stream {
+	c, _ := d.format()
+	s := &stream{
+		chunks: make(chan []float32, streamChunks),
+		free:   make(chan []float32, streamChunks),
+		quit:   make(chan struct{}),
+	}
+	s.setLoop(loop)
+	for i := 0; i < streamChunks; i++ {
+		s.free <- make([]float32, streamFrames*c)
+	}
+	go s.decode(d, c)
+	return s
+}
+
+func (s *stream) setLoop(loop bool) {
+	var l int32
+	if loop {
+		l = 1
+	}
+	atomic.StoreInt32(&s.loop, l)
+}
+
+// stop ends the decoding goroutine. It must be called only once.
+func (s *stream) stop() {
+	close(s.quit)
+}
+
+// decode runs in its own goroutine. Errors (and panics, as the files may be
+// corrupt) end the stream.
+func (s *stream) decode(d decoder, channels int) {
+	defer close(s.chunks)
+	defer func() {
+		if r := recover(); r != nil {
+			warning.Printf("while decoding stream: %s", fmt.Sprint(r))
+		}
+	}()
+
+	for {
+		var b []float32
+		select {
+		case b = <-s.free:
+		case <-s.quit:
+			return
+		}
+
+		m, err := d.read(b)
+		if m == 0 && err == nil && atomic.LoadInt32(&s.loop) != 0 {
+			err = d.rewind()
+			if err == nil {
+				m, err = d.read(b)
+			}
+		}
+		if err != nil {
+			warning.Printf("while decoding stream: %s", err)
+			return
+		}
+		if m == 0 {
+			return
+		}
+
+		select {
+		case s.chunks <- b[:m*channels]:
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
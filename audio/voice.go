@@ -0,0 +1,260 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package audio
+
+//------------------------------------------------------------------------------
+
+// A Voice is a sound (or a music stream) being played. Its settings can be
+// changed at any time, and take effect with the next buffer of the mixer.
+type Voice struct {
+	bus    *Bus
+	sound  *Sound
+	stream *stream
+
+	volume float32
+	pan    float32
+	pitch  float32
+	loop   bool
+
+	fade     float32 // current fade gain
+	fadeEnd  float32
+	fadeStep float32 // per output frame
+	fadeStop bool    // stop once faded out
+
+	channels int
+	rate     int
+	buf      []float32 // decoded samples
+	chunk    []float32 // storage for buf, for streams
+	pos      float64   // in frames, relative to the start of buf
+	ended    bool      // no more samples in the stream
+	done     bool
+}
+
+// Limits of the pitch factor.
+const (
+	minPitch = 1.0 / 64
+	maxPitch = 64
+)
+
+func newVoice(b *Bus) *Voice {
+	return &Voice{
+		bus:     b,
+		volume:  1,
+		pitch:   1,
+		fade:    1,
+		fadeEnd: 1,
+	}
+}
+
+// start adds the voice to the mixer.
+func (v *Voice) start() *Voice {
+	mixer.Lock()
+	mixer.voices = append(mixer.voices, v)
+	mixer.Unlock()
+	return v
+}
+
+//------------------------------------------------------------------------------
+
+// SetVolume changes the volume of the voice (1 is the original volume).
+func (v *Voice) SetVolume(volume float32) {
+	mixer.Lock()
+	v.volume = volume
+	mixer.Unlock()
+}
+
+// SetPan changes the stereo position of the voice, from -1 (left) to 1
+// (right).
+func (v *Voice) SetPan(pan float32) {
+	switch {
+	case pan < -1:
+		pan = -1
+	case pan > 1:
+		pan = 1
+	}
+	mixer.Lock()
+	v.pan = pan
+	mixer.Unlock()
+}
+
+// SetPitch changes the playback speed of the voice (1 is the original speed,
+// 2 is one octave higher).
+func (v *Voice) SetPitch(pitch float32) {
+	switch {
+	case pitch < minPitch:
+		pitch = minPitch
+	case pitch > maxPitch:
+		pitch = maxPitch
+	}
+	mixer.Lock()
+	v.pitch = pitch
+	mixer.Unlock()
+}
+
+// SetLoop changes whether the voice restarts from the beginning when it
+// reaches the end.
+func (v *Voice) SetLoop(loop bool) {
+	mixer.Lock()
+	v.loop = loop
+	if v.stream != nil {
+		v.stream.setLoop(loop)
+	}
+	mixer.Unlock()
+}
+
+// FadeTo progressively changes the volume of the voice, by a factor going
+// from its current value to f, over a duration in seconds. The factor is
+// independent from SetVolume.
+func (v *Voice) FadeTo(f float32, duration float64) {
+	mixer.Lock()
+	v.setFade(f, duration, false)
+	mixer.Unlock()
+}
+
+// FadeOut progressively silences the voice over a duration in seconds, then
+// stops it.
+func (v *Voice) FadeOut(duration float64) {
+	mixer.Lock()
+	v.setFade(0, duration, true)
+	mixer.Unlock()
+}
+
+func (v *Voice) setFade(f float32, duration float64, stop bool) {
+	v.fadeEnd = f
+	v.fadeStop = stop
+	n := float32(duration * float64(mixer.rate))
+	if n < 1 {
+		v.fade = f
+		v.fadeStep = 0
+		if stop && f <= 0 {
+			v.done = true
+		}
+		return
+	}
+	v.fadeStep = (f - v.fade) / n
+}
+
+// Stop stops the voice immediately.
+func (v *Voice) Stop() {
+	mixer.Lock()
+	v.done = true
+	mixer.Unlock()
+}
+
+// Playing returns true until the voice reaches the end of a non-looping
+// sound, or is stopped.
+func (v *Voice) Playing() bool {
+	mixer.Lock()
+	defer mixer.Unlock()
+	return !v.done
+}
+
+//------------------------------------------------------------------------------
+
+// mix adds the voice to out (interleaved stereo). The mixer must be locked.
+func (v *Voice) mix(out []float32, master float32) {
+	step := float64(v.pitch) * float64(v.rate) / float64(mixer.rate)
+	gain := v.volume * v.bus.volume * master
+	left, right := gain, gain
+	if v.pan > 0 {
+		left *= 1 - v.pan
+	} else {
+		right *= 1 + v.pan
+	}
+	c := v.channels
+
+	for i := 0; i+1 < len(out); i += 2 {
+		if v.fadeStep != 0 {
+			v.fade += v.fadeStep
+			if (v.fadeStep > 0 && v.fade >= v.fadeEnd) || (v.fadeStep < 0 && v.fade <= v.fadeEnd) {
+				v.fade = v.fadeEnd
+				v.fadeStep = 0
+			}
+		}
+		if v.fadeStop && v.fadeStep == 0 && v.fade <= 0 {
+			v.done = true
+			return
+		}
+
+		n := len(v.buf) / c
+		k := int(v.pos)
+		for k+1 >= n && v.stream != nil && !v.ended {
+			if !v.refill() {
+				// The decoder is late
+				return
+			}
+			n = len(v.buf) / c
+			k = int(v.pos)
+		}
+		if k >= n {
+			if v.stream != nil || !v.loop {
+				v.done = true
+				return
+			}
+			for v.pos >= float64(n) {
+				v.pos -= float64(n)
+			}
+			k = int(v.pos)
+		}
+		k1 := k + 1
+		if k1 >= n {
+			if v.stream == nil && v.loop {
+				k1 = 0
+			} else {
+				k1 = k
+			}
+		}
+
+		f := float32(v.pos - float64(k))
+		l0, l1 := v.buf[k*c], v.buf[k1*c]
+		l := l0 + f*(l1-l0)
+		r := l
+		if c == 2 {
+			r0, r1 := v.buf[k*c+1], v.buf[k1*c+1]
+			r = r0 + f*(r1-r0)
+		}
+		out[i] += l * left * v.fade
+		out[i+1] += r * right * v.fade
+
+		v.pos += step
+	}
+}
+
+// refill takes the next chunk decoded by the stream, if ready. The last frame
+// of the previous chunk is kept, for the interpolation.
+func (v *Voice) refill() bool {
+	var b []float32
+	select {
+	case b = <-v.stream.chunks:
+	default:
+		return false
+	}
+
+	c := v.channels
+	n := len(v.buf) / c
+	keep := 0
+	if n > 0 {
+		keep = 1
+		copy(v.chunk[:c], v.buf[(n-1)*c:n*c])
+	}
+	if b == nil {
+		v.ended = true
+	} else {
+		copy(v.chunk[keep*c:], b)
+		v.stream.free <- b[:cap(b)]
+	}
+
+	v.pos -= float64(n - keep)
+	v.buf = v.chunk[:keep*c+len(b)]
+	return true
+}
+
+// release is called once the voice is removed from the mixer.
+func (v *Voice) release() {
+	if v.stream != nil {
+		v.stream.stop()
+	}
+}
+
+//------------------------------------------------------------------------------
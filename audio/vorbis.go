@@ -0,0 +1,338 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package audio
+
+//------------------------------------------------------------------------------
+
+import (
+	"errors"
+	"math"
+)
+
+//------------------------------------------------------------------------------
+
+// A vorbisDecoder decodes an Ogg Vorbis file, stored in memory. Only floor type
+// 1 is supported (type 0 has not been produced by encoders for a long time).
+type vorbisDecoder struct {
+	ogg       *oggReader
+	channels  int
+	rate      int
+	blocksize [2]int
+
+	books    []codebook
+	floors   []floor1
+	residues []residue
+	mappings []mapping
+	modes    []mode
+
+	slopes [2][]float32 // rising halves of the windows, for each block size
+	imdct  [2]*imdct
+
+	// State between packets
+	prevSize int         // 0 before the first audio packet
+	overlap  [][]float32 // right half of the previous block, for each channel
+	total    int64       // frames produced so far
+	pcm      []float32   // decoded frames not yet read
+	pcmPos   int
+
+	// Scratch buffers
+	spectrum [][]float32
+	block    [][]float32
+	floorY   [][]int
+	final    []int
+	step2    []bool
+	unused   []bool
+	skip     []bool
+	scratch  []float32
+}
+
+func newVorbisDecoder(data []byte) (*vorbisDecoder, error) {
+	d := &vorbisDecoder{ogg: newOggReader(data)}
+
+	p, err := d.ogg.next()
+	if err != nil {
+		return nil, err
+	}
+	err = d.readIdentification(p)
+	if err != nil {
+		return nil, err
+	}
+	p, err = d.ogg.next()
+	if err != nil {
+		return nil, err
+	}
+	if len(p) < 7 || p[0] != 3 || string(p[1:7]) != "vorbis" {
+		return nil, errors.New("invalid Vorbis comment header")
+	}
+	p, err = d.ogg.next()
+	if err != nil {
+		return nil, err
+	}
+	err = d.readSetup(p)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, n := range d.blocksize {
+		d.imdct[i] = newIMDCT(n)
+		s := make([]float32, n/2)
+		for j := range s {
+			x := math.Sin((float64(j) + 0.5) / float64(n/2) * math.Pi / 2)
+			s[j] = float32(math.Sin(math.Pi / 2 * x * x))
+		}
+		d.slopes[i] = s
+	}
+
+	c, n := d.channels, d.blocksize[1]
+	d.overlap = make([][]float32, c)
+	d.spectrum = make([][]float32, c)
+	d.block = make([][]float32, c)
+	d.floorY = make([][]int, c)
+	for i := 0; i < c; i++ {
+		d.overlap[i] = make([]float32, 0, n/2)
+		d.spectrum[i] = make([]float32, n/2)
+		d.block[i] = make([]float32, n)
+		d.floorY[i] = make([]int, 65)
+	}
+	d.final = make([]int, 65)
+	d.step2 = make([]bool, 65)
+	d.unused = make([]bool, c)
+	d.skip = make([]bool, c)
+	d.scratch = make([]float32, c*n/2)
+	return d, nil
+}
+
+func (d *vorbisDecoder) readIdentification(p []byte) error {
+	if len(p) < 30 || p[0] != 1 || string(p[1:7]) != "vorbis" {
+		return errors.New("invalid Vorbis identification header")
+	}
+	b := &bitReader{data: p[7:]}
+	if b.read(32) != 0 {
+		return errors.New("unsupported Vorbis version")
+	}
+	d.channels = int(b.read(8))
+	d.rate = int(b.read(32))
+	b.read(32)
+	b.read(32)
+	b.read(32)
+	d.blocksize[0] = 1 << b.read(4)
+	d.blocksize[1] = 1 << b.read(4)
+	if d.blocksize[0] < 64 || d.blocksize[0] > d.blocksize[1] || d.blocksize[1] > 8192 || !b.flag() {
+		return errors.New("invalid Vorbis identification header")
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+func (d *vorbisDecoder) format() (channels, rate int) {
+	return d.channels, d.rate
+}
+
+func (d *vorbisDecoder) read(p []float32) (int, error) {
+	c := d.channels
+	p = p[:len(p)/c*c]
+	n := 0
+	for n*c < len(p) {
+		if d.pcmPos >= len(d.pcm) {
+			ok, err := d.decodePacket()
+			if err != nil {
+				return n, err
+			}
+			if !ok {
+				break
+			}
+			continue
+		}
+		k := copy(p[n*c:], d.pcm[d.pcmPos:])
+		d.pcmPos += k
+		n += k / c
+	}
+	return n, nil
+}
+
+func (d *vorbisDecoder) rewind() error {
+	d.ogg.rewind()
+	for i := 0; i < 3; i++ {
+		p, err := d.ogg.next()
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			return errors.New("invalid Vorbis stream")
+		}
+	}
+	d.prevSize = 0
+	d.total = 0
+	d.pcm = d.pcm[:0]
+	d.pcmPos = 0
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// decodePacket decodes the next audio packet into d.pcm. It returns false at
+// the end of the stream. Invalid packets are ignored.
+func (d *vorbisDecoder) decodePacket() (bool, error) {
+	p, err := d.ogg.next()
+	if err != nil || p == nil {
+		return false, err
+	}
+	d.pcm = d.pcm[:0]
+	d.pcmPos = 0
+
+	b := &bitReader{data: p}
+	if b.flag() {
+		return true, nil
+	}
+	m := int(b.read(ilog(len(d.modes) - 1)))
+	if m >= len(d.modes) {
+		return true, nil
+	}
+	md := d.modes[m]
+	mp := &d.mappings[md.mapping]
+	long := 0
+	prevLong, nextLong := false, false
+	if md.long {
+		long = 1
+		prevLong = b.flag()
+		nextLong = b.flag()
+	}
+	if b.eop {
+		return true, nil
+	}
+	n := d.blocksize[long]
+	h := n / 2
+
+	// Floors
+	for ch := 0; ch < d.channels; ch++ {
+		f := &d.floors[mp.floors[mp.mux[ch]]]
+		d.unused[ch] = !f.decode(b, d.books, d.floorY[ch])
+		d.skip[ch] = d.unused[ch]
+	}
+	for i := range mp.magnitude {
+		mg, an := mp.magnitude[i], mp.angle[i]
+		if !d.skip[mg] || !d.skip[an] {
+			d.skip[mg], d.skip[an] = false, false
+		}
+	}
+
+	// Residues
+	for ch := 0; ch < d.channels; ch++ {
+		s := d.spectrum[ch][:h]
+		for i := range s {
+			s[i] = 0
+		}
+	}
+	for sm, r := range mp.residues {
+		var vv [][]float32
+		var skip []bool
+		for ch := 0; ch < d.channels; ch++ {
+			if mp.mux[ch] == sm {
+				vv = append(vv, d.spectrum[ch][:h])
+				skip = append(skip, d.skip[ch])
+			}
+		}
+		d.residues[r].decode(b, d.books, vv, skip, h, d.scratch)
+	}
+
+	// Inverse coupling
+	for i := len(mp.magnitude) - 1; i >= 0; i-- {
+		mv, av := d.spectrum[mp.magnitude[i]][:h], d.spectrum[mp.angle[i]][:h]
+		for j := range mv {
+			m, a := mv[j], av[j]
+			switch {
+			case m > 0 && a > 0:
+				mv[j], av[j] = m, m-a
+			case m > 0:
+				mv[j], av[j] = m+a, m
+			case a > 0:
+				mv[j], av[j] = m, m+a
+			default:
+				mv[j], av[j] = m-a, m
+			}
+		}
+	}
+
+	// Floor curves, inverse MDCT and windowing
+	ls, ln := 0, h
+	if md.long && !prevLong {
+		ls, ln = n/4-d.blocksize[0]/4, d.blocksize[0]/2
+	}
+	rs, rn := h, h
+	if md.long && !nextLong {
+		rs, rn = n*3/4-d.blocksize[0]/4, d.blocksize[0]/2
+	}
+	lw, rw := d.slope(ln), d.slope(rn)
+	for ch := 0; ch < d.channels; ch++ {
+		s := d.spectrum[ch][:h]
+		if d.unused[ch] {
+			for i := range s {
+				s[i] = 0
+			}
+		} else {
+			d.floors[mp.floors[mp.mux[ch]]].apply(d.floorY[ch], s, d.final, d.step2)
+		}
+		o := d.block[ch][:n]
+		d.imdct[long].inverse(s, o)
+		for i := 0; i < ls; i++ {
+			o[i] = 0
+		}
+		for i := 0; i < ln; i++ {
+			o[ls+i] *= lw[i]
+		}
+		for i := 0; i < rn; i++ {
+			o[rs+i] *= rw[rn-1-i]
+		}
+		for i := rs + rn; i < n; i++ {
+			o[i] = 0
+		}
+	}
+
+	// Overlap-add with the previous block
+	if d.prevSize > 0 {
+		count := d.prevSize/4 + n/4
+		if l := d.ogg.last; l >= 0 && d.total+int64(count) > l {
+			count = int(l - d.total)
+			if count < 0 {
+				count = 0
+			}
+		}
+		off := d.prevSize/4 - n/4
+		c := d.channels
+		if cap(d.pcm) < count*c {
+			d.pcm = make([]float32, count*c)
+		}
+		d.pcm = d.pcm[:count*c]
+		for ch := 0; ch < c; ch++ {
+			prev, cur := d.overlap[ch], d.block[ch]
+			for t := 0; t < count; t++ {
+				var s float32
+				if t < len(prev) {
+					s = prev[t]
+				}
+				if j := t - off; j >= 0 && j < h {
+					s += cur[j]
+				}
+				d.pcm[t*c+ch] = s
+			}
+		}
+		d.total += int64(count)
+	}
+	for ch := 0; ch < d.channels; ch++ {
+		d.overlap[ch] = append(d.overlap[ch][:0], d.block[ch][h:n]...)
+	}
+	d.prevSize = n
+	return true, nil
+}
+
+// slope returns the rising half of a window of length n.
+func (d *vorbisDecoder) slope(n int) []float32 {
+	if n == d.blocksize[0]/2 {
+		return d.slopes[0]
+	}
+	return d.slopes[1]
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,458 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package audio
+
+import (
+	"math"
+	"testing"
+)
+
+//------------------------------------------------------------------------------
+
+// A stereoBlock is an audio packet of the stream built by stereoVorbisFile:
+// the values decoded for the floor and residue of each block, and the window
+// flags of the long blocks.
+type stereoBlock struct {
+	long               bool
+	prevLong, nextLong bool
+	floor              [2][3]int // first, last and middle point, per channel
+	residue            []int     // interleaved magnitude and angle, in -1..2
+}
+
+func TestVorbisStereo(t *testing.T) {
+	// Short (64) and long (256) blocks, with all the window transitions
+	modes := []bool{false, false, true, true, false, true, false, false, true}
+	blocks := make([]stereoBlock, len(modes))
+	seed := uint32(1)
+	random := func(n int) int {
+		seed = seed*1664525 + 1013904223
+		return int(seed>>16) % n
+	}
+	for p, long := range modes {
+		b := &blocks[p]
+		b.long = long
+		if long {
+			b.prevLong = p > 0 && modes[p-1]
+			b.nextLong = p+1 < len(modes) && modes[p+1]
+		}
+		for ch := range b.floor {
+			b.floor[ch] = [3]int{30 + random(70), 30 + random(70), 2 * random(5)}
+		}
+		n := blockSize(long)
+		b.residue = make([]int, n)
+		size := 8
+		if long {
+			size = 16
+		}
+		for i := range b.residue {
+			if (i/size+p)%3 != 0 {
+				b.residue[i] = random(4) - 1
+			}
+		}
+	}
+
+	want, ends := stereoReference(blocks)
+	frames := len(want)/2 - 7 // the last packet is partially used
+	want = want[:2*frames]
+	ends[len(ends)-1] = int64(frames)
+
+	d, err := newDecoder(stereoVorbisFile(blocks, ends))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, r := d.format()
+	if c != 2 || r != 11025 {
+		t.Fatalf("format: %d channels at %d Hz", c, r)
+	}
+	for pass := 0; pass < 2; pass++ {
+		s, err := decodeAll(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(s) != len(want) {
+			t.Fatalf("pass %d: decoded %d samples, expected %d", pass, len(s), len(want))
+		}
+		for i := range want {
+			if math.Abs(float64(s[i])-want[i]) > 1e-4 {
+				t.Fatalf("pass %d: sample %d = %g, expected %g", pass, i, s[i], want[i])
+			}
+		}
+		err = d.rewind()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func blockSize(long bool) int {
+	if long {
+		return 256
+	}
+	return 64
+}
+
+//------------------------------------------------------------------------------
+
+// stereoReference computes the interleaved output of the blocks, following the
+// Vorbis specification step by step: inverse coupling, floor curve, inverse
+// MDCT, window, and overlap-add. It also returns the granule position at the
+// end of each packet.
+func stereoReference(blocks []stereoBlock) ([]float64, []int64) {
+	centers := make([]int, len(blocks))
+	for p, b := range blocks {
+		n := blockSize(b.long)
+		if p == 0 {
+			centers[p] = n / 2
+			continue
+		}
+		centers[p] = centers[p-1] + blockSize(blocks[p-1].long)/4 + n/4
+	}
+	last := centers[len(centers)-1]
+	timeline := make([][2]float64, last+1)
+	ends := make([]int64, len(blocks))
+
+	for p, b := range blocks {
+		n := blockSize(b.long)
+		h := n / 2
+		ends[p] = int64(centers[p] - centers[0])
+
+		var spectrum [2][]float64
+		for ch := range spectrum {
+			spectrum[ch] = make([]float64, h)
+		}
+		for k := 0; k < h; k++ {
+			m, a := b.residue[2*k], b.residue[2*k+1]
+			var l, r int
+			switch {
+			case m > 0 && a > 0:
+				l, r = m, m-a
+			case m > 0:
+				l, r = m+a, m
+			case a > 0:
+				l, r = m, m+a
+			default:
+				l, r = m-a, m
+			}
+			spectrum[0][k], spectrum[1][k] = float64(l), float64(r)
+		}
+
+		for ch := range spectrum {
+			curve := floorCurve(b.floor[ch], h)
+			for k := range spectrum[ch] {
+				spectrum[ch][k] *= math.Pow(10, float64(curve[k]-255)*0.546875/20)
+			}
+		}
+
+		w := window(b, n)
+		start := centers[p] - h
+		for i := 0; i < n; i++ {
+			for ch := range spectrum {
+				var y float64
+				for k, x := range spectrum[ch] {
+					y += x * math.Cos(2*math.Pi/float64(n)*(float64(i)+0.5+float64(n)/4)*(float64(k)+0.5))
+				}
+				if t := start + i; t >= 0 && t <= last {
+					timeline[t][ch] += y * w[i]
+				}
+			}
+		}
+	}
+
+	out := make([]float64, 0, 2*(last-centers[0]))
+	for _, s := range timeline[centers[0]:last] {
+		out = append(out, s[0], s[1])
+	}
+	return out, ends
+}
+
+// floorCurve returns the floor of type 1 defined by three points (at 0, h and
+// h/2, the latter predicted from the first two), with a multiplier of 2.
+func floorCurve(f [3]int, h int) []int {
+	y0, y1, val := f[0], f[1], f[2]
+	line := func(x0, y0, x1, y1, x int) int {
+		return y0 + (y1-y0)*(x-x0)/(x1-x0)
+	}
+	xs, ys := []int{0, h}, []int{2 * y0, 2 * y1}
+	if val != 0 {
+		mid := line(0, y0, h, y1, h/2) + val/2
+		xs, ys = []int{0, h / 2, h}, []int{2 * y0, 2 * mid, 2 * y1}
+	}
+	c := make([]int, h)
+	for i := 0; i+1 < len(xs); i++ {
+		for x := xs[i]; x < xs[i+1]; x++ {
+			c[x] = line(xs[i], ys[i], xs[i+1], ys[i+1], x)
+		}
+	}
+	return c
+}
+
+// window returns the window of a block of size n.
+func window(b stereoBlock, n int) []float64 {
+	slope := func(i, l int) float64 {
+		s := math.Sin((float64(i) + 0.5) / float64(l) * math.Pi / 2)
+		return math.Sin(math.Pi / 2 * s * s)
+	}
+	h := n / 2
+	ls, ln := 0, h
+	if b.long && !b.prevLong {
+		ls, ln = n/4-16, 32
+	}
+	rs, rn := h, h
+	if b.long && !b.nextLong {
+		rs, rn = 3*n/4-16, 32
+	}
+	w := make([]float64, n)
+	for i := range w {
+		switch {
+		case i < ls:
+			w[i] = 0
+		case i < ls+ln:
+			w[i] = slope(i-ls, ln)
+		case i < rs:
+			w[i] = 1
+		case i < rs+rn:
+			w[i] = slope(rn-1-(i-rs), rn)
+		default:
+			w[i] = 0
+		}
+	}
+	return w
+}
+
+//------------------------------------------------------------------------------
+
+// stereoVorbisFile returns a stereo Ogg Vorbis file for the blocks, with
+// packets spanning several pages. The setup has an ordered, an unordered and a
+// sparse codebook, a floor of type 1 and a residue of type 2 for each block
+// size, and channel coupling.
+func stereoVorbisFile(blocks []stereoBlock, ends []int64) []byte {
+	var id, comment, setup bitWriter
+	id.bytes(1, 'v', 'o', 'r', 'b', 'i', 's')
+	id.write(0, 32)
+	id.write(2, 8)
+	id.write(11025, 32)
+	id.write(0, 32)
+	id.write(0, 32)
+	id.write(0, 32)
+	id.write(6, 4) // block sizes: 64 and 256
+	id.write(8, 4)
+	id.write(1, 1)
+
+	comment.bytes(3, 'v', 'o', 'r', 'b', 'i', 's')
+	comment.write(300, 32) // a long vendor string, to span pages
+	for i := 0; i < 300; i++ {
+		comment.write('x', 8)
+	}
+	comment.write(0, 32)
+	comment.write(1, 1)
+
+	setup.bytes(5, 'v', 'o', 'r', 'b', 'i', 's')
+	setup.write(2, 8)
+	// Book 0, for the residue classes: ordered, codewords "0" and "1"
+	setup.write(0x564342, 24)
+	setup.write(1, 16)
+	setup.write(2, 24)
+	setup.write(1, 1)
+	setup.write(0, 5)
+	setup.write(2, 2)
+	setup.write(0, 4)
+	// Book 1, for the residue values: pairs of values in -1..2, 4-bit codewords
+	setup.write(0x564342, 24)
+	setup.write(2, 16)
+	setup.write(16, 24)
+	setup.write(0, 1)
+	setup.write(0, 1)
+	for e := 0; e < 16; e++ {
+		setup.write(3, 5)
+	}
+	setup.write(1, 4)
+	setup.write(0x80000000|788<<21|1, 32) // min -1
+	setup.write(788<<21|1, 32)            // delta 1
+	setup.write(1, 4)
+	setup.write(0, 1)
+	for m := 0; m < 4; m++ {
+		setup.write(uint32(m), 2)
+	}
+	// Book 2, for the floor: sparse, even entries only, 5-bit codewords
+	setup.write(0x564342, 24)
+	setup.write(1, 16)
+	setup.write(64, 24)
+	setup.write(0, 1)
+	setup.write(1, 1)
+	for e := 0; e < 64; e++ {
+		if e%2 == 0 {
+			setup.write(1, 1)
+			setup.write(4, 5)
+		} else {
+			setup.write(0, 1)
+		}
+	}
+	setup.write(0, 4)
+
+	setup.write(0, 6) // time domain transforms
+	setup.write(0, 16)
+
+	setup.write(1, 6) // two floors, with a point in the middle
+	for _, bits := range []int{5, 7} {
+		setup.write(1, 16)
+		setup.write(1, 5)
+		setup.write(0, 4)
+		setup.write(0, 3)
+		setup.write(0, 2)
+		setup.write(3, 8) // book 2
+		setup.write(1, 2) // multiplier 2
+		setup.write(uint32(bits), 4)
+		setup.write(1<<uint(bits-1), bits)
+	}
+
+	setup.write(1, 6) // two residues of type 2
+	for _, n := range []int{64, 256} {
+		size := 8
+		if n == 256 {
+			size = 16
+		}
+		setup.write(2, 16)
+		setup.write(0, 24)
+		setup.write(uint32(n), 24)
+		setup.write(uint32(size-1), 24)
+		setup.write(1, 6)
+		setup.write(0, 8)
+		setup.write(0, 3)
+		setup.write(0, 1)
+		setup.write(1, 3)
+		setup.write(0, 1)
+		setup.write(1, 8)
+	}
+
+	setup.write(1, 6) // two mappings, with coupling
+	for i := 0; i < 2; i++ {
+		setup.write(0, 16)
+		setup.write(0, 1)
+		setup.write(1, 1)
+		setup.write(0, 8)
+		setup.write(0, 1)
+		setup.write(1, 1)
+		setup.write(0, 2)
+		setup.write(0, 8)
+		setup.write(uint32(i), 8)
+		setup.write(uint32(i), 8)
+	}
+
+	setup.write(1, 6) // a short and a long mode
+	for i := 0; i < 2; i++ {
+		setup.write(uint32(i), 1)
+		setup.write(0, 16)
+		setup.write(0, 16)
+		setup.write(uint32(i), 8)
+	}
+	setup.write(1, 1)
+
+	codeword := func(w *bitWriter, v, n int) {
+		for i := n - 1; i >= 0; i-- {
+			w.write(uint32(v>>uint(i)&1), 1)
+		}
+	}
+	var audio [][]byte
+	for _, b := range blocks {
+		var a bitWriter
+		a.write(0, 1)
+		size := 8
+		if b.long {
+			size = 16
+			a.write(1, 1)
+			a.write(bit(b.prevLong), 1)
+			a.write(bit(b.nextLong), 1)
+		} else {
+			a.write(0, 1)
+		}
+		for _, f := range b.floor {
+			a.write(1, 1)
+			a.write(uint32(f[0]), 7)
+			a.write(uint32(f[1]), 7)
+			codeword(&a, f[2]/2, 5)
+		}
+		for p := 0; p < len(b.residue)/size; p++ {
+			v := b.residue[p*size : (p+1)*size]
+			class := 0
+			for _, x := range v {
+				if x != 0 {
+					class = 1
+				}
+			}
+			codeword(&a, class, 1)
+			if class == 1 {
+				for i := 0; i < len(v); i += 2 {
+					codeword(&a, v[i]+1+4*(v[i+1]+1), 4)
+				}
+			}
+		}
+		audio = append(audio, a.data)
+	}
+
+	f := oggPage(0x02, 0, 0, id.data)
+	var packets [][]byte
+	var granules []int64
+	packets = append(packets, comment.data, setup.data)
+	granules = append(granules, 0, 0)
+	packets = append(packets, audio...)
+	granules = append(granules, ends...)
+	return append(f, oggPages(1, 3, packets, granules)...)
+}
+
+func bit(b bool) uint32 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// oggPages splits the packets into pages of at most perPage segments, the
+// last one ending the stream.
+func oggPages(seq uint32, perPage int, packets [][]byte, granules []int64) []byte {
+	type segment struct {
+		data   []byte
+		end    bool // of a packet
+		packet int
+	}
+	var segments []segment
+	for i, p := range packets {
+		for len(p) >= 255 {
+			segments = append(segments, segment{p[:255], false, i})
+			p = p[255:]
+		}
+		segments = append(segments, segment{p, true, i})
+	}
+
+	var f []byte
+	continued := false
+	for len(segments) > 0 {
+		n := perPage
+		if n > len(segments) {
+			n = len(segments)
+		}
+		var lacing, body []byte
+		granule := int64(-1)
+		for _, s := range segments[:n] {
+			lacing = append(lacing, byte(len(s.data)))
+			body = append(body, s.data...)
+			if s.end {
+				granule = granules[s.packet]
+			}
+		}
+		var flags byte
+		if continued {
+			flags |= 0x01
+		}
+		if n == len(segments) {
+			flags |= 0x04
+		}
+		continued = !segments[n-1].end
+		f = append(f, oggRawPage(flags, granule, seq, lacing, body)...)
+		segments = segments[n:]
+		seq++
+	}
+	return f
+}
+
+//------------------------------------------------------------------------------
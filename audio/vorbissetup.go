@@ -0,0 +1,786 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package audio
+
+//------------------------------------------------------------------------------
+
+import (
+	"errors"
+	"math"
+)
+
+//------------------------------------------------------------------------------
+
+var errVorbisSetup = errors.New("invalid Vorbis setup header")
+
+// maxCodebookValues limits the size of the vector tables of the codebooks
+// (encoders use much smaller ones).
+const maxCodebookValues = 1 << 20
+
+// A bitReader reads the fields of a Vorbis packet, least significant bit
+// first.
+type bitReader struct {
+	data []byte
+	pos  int  // in bits
+	eop  bool // tried to read past the end of the packet
+}
+
+func (b *bitReader) read(n int) uint32 {
+	var v uint32
+	for i := 0; i < n; {
+		p := b.pos >> 3
+		if p >= len(b.data) {
+			b.eop = true
+			return 0
+		}
+		s := uint(b.pos & 7)
+		t := 8 - int(s)
+		if t > n-i {
+			t = n - i
+		}
+		v |= uint32(b.data[p]>>s&(1<<uint(t)-1)) << uint(i)
+		i += t
+		b.pos += t
+	}
+	return v
+}
+
+func (b *bitReader) flag() bool {
+	return b.read(1) == 1
+}
+
+// remaining returns the number of bits left in the packet.
+func (b *bitReader) remaining() int {
+	return len(b.data)*8 - b.pos
+}
+
+// ilog returns the number of bits needed to represent x.
+func ilog(x int) int {
+	n := 0
+	for x > 0 {
+		n++
+		x >>= 1
+	}
+	return n
+}
+
+func float32Unpack(x uint32) float32 {
+	m := float64(x & 0x1fffff)
+	e := int(x&0x7fe00000>>21) - 788
+	if x&0x80000000 != 0 {
+		m = -m
+	}
+	return float32(math.Ldexp(m, e))
+}
+
+//------------------------------------------------------------------------------
+
+// A codebook maps Huffman codewords to entries, and optionally to vectors.
+type codebook struct {
+	dimensions int
+	entries    int
+	// tree holds pairs of children: positive values are the index of the next
+	// pair, negative values are leaves for entry -v-1, and 0 is unused.
+	tree   []int32
+	full   []bool // for each pair, whether the subtree has no more room
+	values []float32
+}
+
+func (d *vorbisDecoder) readCodebook(b *bitReader) (codebook, error) {
+	var c codebook
+	if b.read(24) != 0x564342 {
+		return c, errVorbisSetup
+	}
+	c.dimensions = int(b.read(16))
+	c.entries = int(b.read(24))
+	if c.dimensions == 0 && c.entries != 0 {
+		return c, errVorbisSetup
+	}
+
+	lengths := make([]uint8, c.entries)
+	if b.flag() {
+		// Ordered
+		l := int(b.read(5)) + 1
+		for i := 0; i < c.entries; {
+			n := int(b.read(ilog(c.entries - i)))
+			if i+n > c.entries || l > 32 {
+				return c, errVorbisSetup
+			}
+			for j := 0; j < n; j++ {
+				lengths[i+j] = uint8(l)
+			}
+			i += n
+			l++
+		}
+	} else {
+		sparse := b.flag()
+		for i := range lengths {
+			if !sparse || b.flag() {
+				lengths[i] = uint8(b.read(5) + 1)
+			}
+		}
+	}
+	if b.eop {
+		return c, errVorbisSetup
+	}
+
+	c.tree = []int32{0, 0}
+	c.full = []bool{false}
+	for e, l := range lengths {
+		if l > 0 && !c.insert(0, 1, int(l), e) {
+			return c, errVorbisSetup
+		}
+	}
+
+	lookup := b.read(4)
+	switch lookup {
+	case 0:
+	case 1, 2:
+		min := float32Unpack(b.read(32))
+		delta := float32Unpack(b.read(32))
+		bits := int(b.read(4)) + 1
+		seq := b.flag()
+		if c.entries*c.dimensions > maxCodebookValues {
+			return c, errVorbisSetup
+		}
+		n := c.entries * c.dimensions
+		if lookup == 1 {
+			n = lookup1Values(c.entries, c.dimensions)
+		}
+		if n*bits > b.remaining() {
+			// Don't trust the sizes of a truncated or corrupt header
+			return c, errVorbisSetup
+		}
+		mult := make([]uint32, n)
+		for i := range mult {
+			mult[i] = b.read(bits)
+		}
+		if b.eop {
+			return c, errVorbisSetup
+		}
+		c.values = make([]float32, c.entries*c.dimensions)
+		for e := 0; e < c.entries; e++ {
+			if lengths[e] == 0 {
+				continue
+			}
+			last := float32(0)
+			div := 1
+			for j := 0; j < c.dimensions; j++ {
+				o := e*c.dimensions + j
+				if lookup == 1 {
+					o = e / div % n
+					div *= n
+				}
+				v := float32(mult[o])*delta + min + last
+				if seq {
+					last = v
+				}
+				c.values[e*c.dimensions+j] = v
+			}
+		}
+	default:
+		return c, errVorbisSetup
+	}
+	return c, nil
+}
+
+// insert adds entry e to the subtree at pair p, as the leftmost leaf available
+// at the given length, i.e. the lowest codeword.
+func (c *codebook) insert(p int32, depth, length, e int) bool {
+	for k := int32(0); k < 2; k++ {
+		v := c.tree[2*p+k]
+		if depth == length {
+			if v != 0 {
+				continue
+			}
+			c.tree[2*p+k] = int32(-e - 1)
+			c.full[p] = c.isFull(2*p) && c.isFull(2*p+1)
+			return true
+		}
+		if v < 0 || (v > 0 && c.full[v]) {
+			continue
+		}
+		if v == 0 {
+			v = int32(len(c.full))
+			c.tree = append(c.tree, 0, 0)
+			c.full = append(c.full, false)
+			c.tree[2*p+k] = v
+		}
+		if c.insert(v, depth+1, length, e) {
+			c.full[p] = c.isFull(2*p) && c.isFull(2*p+1)
+			return true
+		}
+	}
+	return false
+}
+
+func (c *codebook) isFull(i int32) bool {
+	v := c.tree[i]
+	return v < 0 || (v > 0 && c.full[v])
+}
+
+// decode reads a codeword, and returns the corresponding entry, or -1 on error
+// or end of packet.
+func (c *codebook) decode(b *bitReader) int {
+	p := int32(0)
+	for {
+		k := b.read(1)
+		if b.eop {
+			return -1
+		}
+		v := c.tree[2*p+int32(k)]
+		switch {
+		case v < 0:
+			return int(-v - 1)
+		case v == 0:
+			return -1
+		}
+		p = v
+	}
+}
+
+// lookup1Values returns the greatest r such that r^dimensions <= entries.
+func lookup1Values(entries, dimensions int) int {
+	r := int(math.Floor(math.Pow(float64(entries), 1/float64(dimensions))))
+	pow := func(r int) float64 {
+		return math.Pow(float64(r), float64(dimensions))
+	}
+	for pow(r+1) <= float64(entries) {
+		r++
+	}
+	for r > 0 && pow(r) > float64(entries) {
+		r--
+	}
+	return r
+}
+
+//------------------------------------------------------------------------------
+
+type floor1 struct {
+	partitionClass []int
+	classDims      []int
+	classSubs      []int
+	classMaster    []int
+	subclassBooks  [][]int
+	multiplier     int
+	xs             []int
+	sorted         []int // indices of xs, by increasing value
+	low, high      []int // neighbors of each x
+}
+
+var floor1Ranges = [4]int{256, 128, 86, 64}
+
+func (d *vorbisDecoder) readFloor(b *bitReader) (floor1, error) {
+	var f floor1
+	if b.read(16) != 1 {
+		return f, errors.New("unsupported Vorbis floor type")
+	}
+	f.partitionClass = make([]int, b.read(5))
+	maxClass := -1
+	for i := range f.partitionClass {
+		f.partitionClass[i] = int(b.read(4))
+		if f.partitionClass[i] > maxClass {
+			maxClass = f.partitionClass[i]
+		}
+	}
+	f.classDims = make([]int, maxClass+1)
+	f.classSubs = make([]int, maxClass+1)
+	f.classMaster = make([]int, maxClass+1)
+	f.subclassBooks = make([][]int, maxClass+1)
+	for i := 0; i <= maxClass; i++ {
+		f.classDims[i] = int(b.read(3)) + 1
+		f.classSubs[i] = int(b.read(2))
+		if f.classSubs[i] != 0 {
+			f.classMaster[i] = int(b.read(8))
+			if f.classMaster[i] >= len(d.books) {
+				return f, errVorbisSetup
+			}
+		}
+		f.subclassBooks[i] = make([]int, 1<<uint(f.classSubs[i]))
+		for j := range f.subclassBooks[i] {
+			f.subclassBooks[i][j] = int(b.read(8)) - 1
+			if f.subclassBooks[i][j] >= len(d.books) {
+				return f, errVorbisSetup
+			}
+		}
+	}
+	f.multiplier = int(b.read(2)) + 1
+	bits := int(b.read(4))
+	f.xs = []int{0, 1 << uint(bits)}
+	for _, c := range f.partitionClass {
+		for j := 0; j < f.classDims[c]; j++ {
+			f.xs = append(f.xs, int(b.read(bits)))
+		}
+	}
+	if b.eop || len(f.xs) > 65 {
+		return f, errVorbisSetup
+	}
+
+	f.sorted = make([]int, len(f.xs))
+	for i := range f.sorted {
+		f.sorted[i] = i
+	}
+	for i := 1; i < len(f.sorted); i++ {
+		for j := i; j > 0 && f.xs[f.sorted[j]] < f.xs[f.sorted[j-1]]; j-- {
+			f.sorted[j], f.sorted[j-1] = f.sorted[j-1], f.sorted[j]
+		}
+	}
+	for i := 1; i < len(f.sorted); i++ {
+		if f.xs[f.sorted[i]] == f.xs[f.sorted[i-1]] {
+			return f, errVorbisSetup
+		}
+	}
+
+	f.low = make([]int, len(f.xs))
+	f.high = make([]int, len(f.xs))
+	for i := 2; i < len(f.xs); i++ {
+		lo, hi := 0, 1
+		for j := 0; j < i; j++ {
+			if f.xs[j] < f.xs[i] && f.xs[j] > f.xs[lo] {
+				lo = j
+			}
+			if f.xs[j] > f.xs[i] && f.xs[j] < f.xs[hi] {
+				hi = j
+			}
+		}
+		f.low[i], f.high[i] = lo, hi
+	}
+	return f, nil
+}
+
+// decode reads the floor values into y. It returns false if the channel is
+// unused.
+func (f *floor1) decode(b *bitReader, books []codebook, y []int) bool {
+	if !b.flag() {
+		return false
+	}
+	bits := ilog(floor1Ranges[f.multiplier-1] - 1)
+	y[0] = int(b.read(bits))
+	y[1] = int(b.read(bits))
+	o := 2
+	for _, c := range f.partitionClass {
+		dims, subs := f.classDims[c], uint(f.classSubs[c])
+		v := 0
+		if subs > 0 {
+			v = books[f.classMaster[c]].decode(b)
+			if v < 0 {
+				return false
+			}
+		}
+		for j := 0; j < dims; j++ {
+			k := f.subclassBooks[c][v&(1<<subs-1)]
+			v >>= subs
+			y[o+j] = 0
+			if k >= 0 {
+				y[o+j] = books[k].decode(b)
+				if y[o+j] < 0 {
+					return false
+				}
+			}
+		}
+		o += dims
+	}
+	return !b.eop
+}
+
+// apply multiplies v by the curve of the floor defined by the values y.
+func (f *floor1) apply(y []int, v []float32, final []int, step2 []bool) {
+	r := floor1Ranges[f.multiplier-1]
+	final[0], final[1] = y[0], y[1]
+	step2[0], step2[1] = true, true
+	for i := 2; i < len(f.xs); i++ {
+		lo, hi := f.low[i], f.high[i]
+		p := renderPoint(f.xs[lo], final[lo], f.xs[hi], final[hi], f.xs[i])
+		hiroom, loroom := r-p, p
+		room := 2 * loroom
+		if hiroom < loroom {
+			room = 2 * hiroom
+		}
+		val := y[i]
+		if val == 0 {
+			step2[i] = false
+			final[i] = p
+			continue
+		}
+		step2[lo], step2[hi], step2[i] = true, true, true
+		switch {
+		case val >= room && hiroom > loroom:
+			final[i] = val - loroom + p
+		case val >= room:
+			final[i] = p - val + hiroom - 1
+		case val%2 == 1:
+			final[i] = p - (val+1)/2
+		default:
+			final[i] = p + val/2
+		}
+	}
+
+	hx, hy := 0, 0
+	lx, ly := 0, final[0]*f.multiplier
+	for _, i := range f.sorted[1:] {
+		if step2[i] {
+			hx, hy = f.xs[i], final[i]*f.multiplier
+			renderLine(lx, ly, hx, hy, v)
+			lx, ly = hx, hy
+		}
+	}
+	if hx < len(v) {
+		renderLine(hx, hy, len(v), hy, v)
+	}
+}
+
+func renderPoint(x0, y0, x1, y1, x int) int {
+	dy := y1 - y0
+	adx := x1 - x0
+	ady := dy
+	if ady < 0 {
+		ady = -ady
+	}
+	off := ady * (x - x0) / adx
+	if dy < 0 {
+		return y0 - off
+	}
+	return y0 + off
+}
+
+func renderLine(x0, y0, x1, y1 int, v []float32) {
+	dy := y1 - y0
+	adx := x1 - x0
+	ady := dy
+	if ady < 0 {
+		ady = -ady
+	}
+	base := dy / adx
+	sy := base + 1
+	if dy < 0 {
+		sy = base - 1
+	}
+	ab := base
+	if ab < 0 {
+		ab = -ab
+	}
+	ady -= ab * adx
+	y, e := y0, 0
+	if x0 < len(v) {
+		v[x0] *= inverseDB(y)
+	}
+	for x := x0 + 1; x < x1 && x < len(v); x++ {
+		e += ady
+		if e >= adx {
+			e -= adx
+			y += sy
+		} else {
+			y += base
+		}
+		v[x] *= inverseDB(y)
+	}
+}
+
+var inverseDBTable = func() (t [256]float32) {
+	for i := range t {
+		t[i] = float32(math.Pow(10, float64(i-255)*0.546875/20))
+	}
+	return t
+}()
+
+func inverseDB(y int) float32 {
+	switch {
+	case y < 0:
+		y = 0
+	case y > 255:
+		y = 255
+	}
+	return inverseDBTable[y]
+}
+
+//------------------------------------------------------------------------------
+
+type residue struct {
+	kind          int
+	begin, end    int
+	partitionSize int
+	classes       int
+	classbook     int
+	books         [][8]int
+}
+
+func (d *vorbisDecoder) readResidue(b *bitReader) (residue, error) {
+	var r residue
+	r.kind = int(b.read(16))
+	if r.kind > 2 {
+		return r, errVorbisSetup
+	}
+	r.begin = int(b.read(24))
+	r.end = int(b.read(24))
+	r.partitionSize = int(b.read(24)) + 1
+	r.classes = int(b.read(6)) + 1
+	r.classbook = int(b.read(8))
+	if r.classbook >= len(d.books) {
+		return r, errVorbisSetup
+	}
+	cascade := make([]uint32, r.classes)
+	for i := range cascade {
+		cascade[i] = b.read(3)
+		if b.flag() {
+			cascade[i] |= b.read(5) << 3
+		}
+	}
+	r.books = make([][8]int, r.classes)
+	for i := range r.books {
+		for j := 0; j < 8; j++ {
+			r.books[i][j] = -1
+			if cascade[i]&(1<<uint(j)) != 0 {
+				r.books[i][j] = int(b.read(8))
+				if r.books[i][j] >= len(d.books) {
+					return r, errVorbisSetup
+				}
+			}
+		}
+	}
+	return r, nil
+}
+
+// decode adds the residue to the vectors v (one per channel) of length n.
+// Vectors marked in skip are not decoded.
+func (r *residue) decode(b *bitReader, books []codebook, v [][]float32, skip []bool, n int, scratch []float32) {
+	if r.kind == 2 {
+		any := false
+		for _, s := range skip {
+			any = any || !s
+		}
+		if !any {
+			return
+		}
+		c := len(v)
+		w := scratch[:n*c]
+		for i := range w {
+			w[i] = 0
+		}
+		r.decodeVectors(b, books, [][]float32{w}, []bool{false}, n*c)
+		for i, s := range w {
+			v[i%c][i/c] = s
+		}
+		return
+	}
+	r.decodeVectors(b, books, v, skip, n)
+}
+
+func (r *residue) decodeVectors(b *bitReader, books []codebook, v [][]float32, skip []bool, n int) {
+	begin, end := r.begin, r.end
+	if begin > n {
+		begin = n
+	}
+	if end > n {
+		end = n
+	}
+	size := r.partitionSize
+	parts := (end - begin) / size
+	if parts <= 0 {
+		return
+	}
+	cb := &books[r.classbook]
+	classes := make([][]int, len(v))
+	for i := range classes {
+		classes[i] = make([]int, parts+cb.dimensions)
+	}
+
+	for pass := 0; pass < 8; pass++ {
+		for p := 0; p < parts; {
+			if pass == 0 {
+				for j := range v {
+					if skip[j] {
+						continue
+					}
+					t := cb.decode(b)
+					if t < 0 {
+						return
+					}
+					for i := cb.dimensions - 1; i >= 0; i-- {
+						classes[j][p+i] = t % r.classes
+						t /= r.classes
+					}
+				}
+			}
+			for i := 0; i < cb.dimensions && p < parts; i++ {
+				for j := range v {
+					if skip[j] {
+						continue
+					}
+					k := r.books[classes[j][p]][pass]
+					if k < 0 {
+						continue
+					}
+					o := begin + p*size
+					if !r.decodePartition(b, &books[k], v[j][o:o+size]) {
+						return
+					}
+				}
+				p++
+			}
+		}
+	}
+}
+
+func (r *residue) decodePartition(b *bitReader, book *codebook, v []float32) bool {
+	dim := book.dimensions
+	if book.values == nil || dim == 0 {
+		return false
+	}
+	if r.kind == 0 {
+		step := len(v) / dim
+		for i := 0; i < step; i++ {
+			e := book.decode(b)
+			if e < 0 {
+				return false
+			}
+			for j, s := range book.values[e*dim : (e+1)*dim] {
+				v[i+j*step] += s
+			}
+		}
+		return true
+	}
+	for i := 0; i < len(v); {
+		e := book.decode(b)
+		if e < 0 {
+			return false
+		}
+		for _, s := range book.values[e*dim : (e+1)*dim] {
+			if i < len(v) {
+				v[i] += s
+				i++
+			}
+		}
+	}
+	return true
+}
+
+//------------------------------------------------------------------------------
+
+type mapping struct {
+	magnitude, angle []int
+	mux              []int
+	floors, residues []int // for each submap
+}
+
+func (d *vorbisDecoder) readMapping(b *bitReader) (mapping, error) {
+	var m mapping
+	if b.read(16) != 0 {
+		return m, errVorbisSetup
+	}
+	submaps := 1
+	if b.flag() {
+		submaps = int(b.read(4)) + 1
+	}
+	if b.flag() {
+		steps := int(b.read(8)) + 1
+		bits := ilog(d.channels - 1)
+		for i := 0; i < steps; i++ {
+			mg, an := int(b.read(bits)), int(b.read(bits))
+			if mg == an || mg >= d.channels || an >= d.channels {
+				return m, errVorbisSetup
+			}
+			m.magnitude = append(m.magnitude, mg)
+			m.angle = append(m.angle, an)
+		}
+	}
+	if b.read(2) != 0 {
+		return m, errVorbisSetup
+	}
+	m.mux = make([]int, d.channels)
+	if submaps > 1 {
+		for i := range m.mux {
+			m.mux[i] = int(b.read(4))
+			if m.mux[i] >= submaps {
+				return m, errVorbisSetup
+			}
+		}
+	}
+	m.floors = make([]int, submaps)
+	m.residues = make([]int, submaps)
+	for i := 0; i < submaps; i++ {
+		b.read(8)
+		m.floors[i] = int(b.read(8))
+		m.residues[i] = int(b.read(8))
+		if m.floors[i] >= len(d.floors) || m.residues[i] >= len(d.residues) {
+			return m, errVorbisSetup
+		}
+	}
+	return m, nil
+}
+
+type mode struct {
+	long    bool
+	mapping int
+}
+
+//------------------------------------------------------------------------------
+
+// readSetup parses the third header of a Vorbis stream.
+func (d *vorbisDecoder) readSetup(p []byte) error {
+	if len(p) < 7 || p[0] != 5 || string(p[1:7]) != "vorbis" {
+		return errVorbisSetup
+	}
+	b := &bitReader{data: p[7:]}
+
+	d.books = make([]codebook, b.read(8)+1)
+	for i := range d.books {
+		var err error
+		d.books[i], err = d.readCodebook(b)
+		if err != nil {
+			return err
+		}
+	}
+
+	n := int(b.read(6)) + 1
+	for i := 0; i < n; i++ {
+		if b.read(16) != 0 {
+			return errVorbisSetup
+		}
+	}
+
+	d.floors = make([]floor1, b.read(6)+1)
+	for i := range d.floors {
+		var err error
+		d.floors[i], err = d.readFloor(b)
+		if err != nil {
+			return err
+		}
+	}
+
+	d.residues = make([]residue, b.read(6)+1)
+	for i := range d.residues {
+		var err error
+		d.residues[i], err = d.readResidue(b)
+		if err != nil {
+			return err
+		}
+	}
+
+	d.mappings = make([]mapping, b.read(6)+1)
+	for i := range d.mappings {
+		var err error
+		d.mappings[i], err = d.readMapping(b)
+		if err != nil {
+			return err
+		}
+	}
+
+	d.modes = make([]mode, b.read(6)+1)
+	for i := range d.modes {
+		d.modes[i].long = b.flag()
+		b.read(16)
+		b.read(16)
+		d.modes[i].mapping = int(b.read(8))
+		if d.modes[i].mapping >= len(d.mappings) {
+			return errVorbisSetup
+		}
+	}
+
+	if !b.flag() || b.eop {
+		return errVorbisSetup
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,130 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package audio
+
+//------------------------------------------------------------------------------
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"strconv"
+)
+
+//------------------------------------------------------------------------------
+
+// WAV sample formats.
+const (
+	wavPCM        = 1
+	wavFloat      = 3
+	wavExtensible = 0xFFFE
+)
+
+// A wavDecoder reads the samples of a WAV file, stored in memory.
+type wavDecoder struct {
+	channels int
+	rate     int
+	float    bool
+	bytes    int // per sample
+	data     []byte
+	pos      int
+}
+
+func newWAVDecoder(b []byte) (*wavDecoder, error) {
+	var d wavDecoder
+	fmtFound := false
+	b = b[12:]
+	for len(b) >= 8 {
+		id := string(b[:4])
+		n := int(binary.LittleEndian.Uint32(b[4:8]))
+		b = b[8:]
+		if n > len(b) {
+			if id != "data" {
+				return nil, errors.New("truncated WAV file")
+			}
+			n = len(b) // Tolerate streaming writers
+		}
+		c := b[:n]
+		if n%2 == 1 && n < len(b) {
+			n++
+		}
+		b = b[n:]
+
+		switch id {
+		case "fmt ":
+			if len(c) < 16 {
+				return nil, errors.New("invalid WAV format chunk")
+			}
+			f := binary.LittleEndian.Uint16(c[0:2])
+			d.channels = int(binary.LittleEndian.Uint16(c[2:4]))
+			d.rate = int(binary.LittleEndian.Uint32(c[4:8]))
+			bits := int(binary.LittleEndian.Uint16(c[14:16]))
+			if f == wavExtensible {
+				if len(c) < 26 {
+					return nil, errors.New("invalid WAV format chunk")
+				}
+				f = binary.LittleEndian.Uint16(c[24:26])
+			}
+			switch {
+			case f == wavPCM && (bits == 8 || bits == 16 || bits == 24 || bits == 32):
+			case f == wavFloat && (bits == 32 || bits == 64):
+				d.float = true
+			default:
+				return nil, errors.New("unsupported WAV format " + strconv.Itoa(int(f)) +
+					" with " + strconv.Itoa(bits) + " bits")
+			}
+			d.bytes = bits / 8
+			fmtFound = true
+		case "data":
+			d.data = c
+		}
+	}
+	if !fmtFound || d.data == nil {
+		return nil, errors.New("invalid WAV file")
+	}
+	if d.channels < 1 {
+		return nil, errors.New("invalid number of channels in WAV file")
+	}
+	return &d, nil
+}
+
+func (d *wavDecoder) format() (channels, rate int) {
+	return d.channels, d.rate
+}
+
+func (d *wavDecoder) read(p []float32) (int, error) {
+	fs := d.bytes * d.channels
+	n := len(p) / d.channels
+	if r := (len(d.data) - d.pos) / fs; n > r {
+		n = r
+	}
+	b := d.data[d.pos : d.pos+n*fs]
+	for i := range p[:n*d.channels] {
+		s := b[i*d.bytes:]
+		switch {
+		case d.float && d.bytes == 4:
+			p[i] = math.Float32frombits(binary.LittleEndian.Uint32(s))
+		case d.float:
+			p[i] = float32(math.Float64frombits(binary.LittleEndian.Uint64(s)))
+		case d.bytes == 1:
+			p[i] = (float32(s[0]) - 128) / (1 << 7)
+		case d.bytes == 2:
+			p[i] = float32(int16(binary.LittleEndian.Uint16(s))) / (1 << 15)
+		case d.bytes == 3:
+			v := int32(uint32(s[0])<<8|uint32(s[1])<<16|uint32(s[2])<<24) >> 8
+			p[i] = float32(v) / (1 << 23)
+		default:
+			p[i] = float32(int32(binary.LittleEndian.Uint32(s))) / (1 << 31)
+		}
+	}
+	d.pos += n * fs
+	return n, nil
+}
+
+func (d *wavDecoder) rewind() error {
+	d.pos = 0
+	return nil
+}
+
+//------------------------------------------------------------------------------
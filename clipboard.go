@@ -0,0 +1,25 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package carol
+
+//------------------------------------------------------------------------------
+
+import (
+	"github.com/drakmaniso/carol/internal"
+)
+
+//------------------------------------------------------------------------------
+
+// Clipboard returns the text content of the system clipboard, or an empty
+// string if it doesn't contain text.
+func Clipboard() (string, error) {
+	return internal.ClipboardText()
+}
+
+// SetClipboard copies text to the system clipboard.
+func SetClipboard(text string) error {
+	return internal.ClipboardSetText(text)
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,55 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package carol_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/drakmaniso/carol"
+)
+
+//------------------------------------------------------------------------------
+
+type dropLoop struct {
+	carol.Handlers
+	files, texts []string
+}
+
+func (l *dropLoop) Setup() error            { return nil }
+func (l *dropLoop) Update() error           { return nil }
+func (l *dropLoop) Draw(_, _ float64) error { return nil }
+func (l *dropLoop) FileDropped(path string) { l.files = append(l.files, path) }
+func (l *dropLoop) TextDropped(text string) { l.texts = append(l.texts, text) }
+
+//------------------------------------------------------------------------------
+
+func TestDrop(t *testing.T) {
+	ts := carol.TimeStep()
+	script := carol.Script{
+		{Kind: carol.EventFileDropped, Time: ts, Text: "/tmp/a.png"},
+		{Kind: carol.EventFileDropped, Time: ts, Text: "/tmp/b.png"},
+		{Kind: carol.EventTextDropped, Time: 2 * ts, Text: "hello"},
+	}
+
+	var l dropLoop
+	s, err := carol.Simulate(&l, &script, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	err = s.Step(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(l.files, []string{"/tmp/a.png", "/tmp/b.png"}) {
+		t.Errorf("got dropped files %q", l.files)
+	}
+	if !reflect.DeepEqual(l.texts, []string{"hello"}) {
+		t.Errorf("got dropped texts %q", l.texts)
+	}
+}
+
+//------------------------------------------------------------------------------
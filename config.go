@@ -0,0 +1,154 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package carol
+
+//------------------------------------------------------------------------------
+
+import (
+	"github.com/drakmaniso/carol/internal"
+)
+
+//------------------------------------------------------------------------------
+
+// Config describes the setup of the game window and pixel screen.
+//
+// The configuration is built by Run from several layers, each one overriding
+// the previous ones:
+//
+// - the default values;
+//
+// - the options passed to Run;
+//
+// - the configuration file "init.json" next to the executable, if present;
+//
+// - the per-user settings file, where runtime changes (e.g. toggling
+// fullscreen) are saved;
+//
+// - the environment variables named after the fields, e.g.
+// CAROL_FULLSCREEN=true or CAROL_WINDOWSIZE=800,600;
+//
+// - the command-line arguments, e.g. -carol.Fullscreen=true or
+// -carol.ScreenMode=Zoom.
+//
+// The resulting configuration is validated before opening the window.
+type Config = internal.Configuration
+
+// An Option modifies the configuration of the game. Options are passed to Run.
+type Option func(*Config)
+
+// GetConfig returns the current configuration.
+func GetConfig() Config {
+	return internal.Config
+}
+
+//------------------------------------------------------------------------------
+
+// Title sets the title of the game window.
+func Title(t string) Option {
+	return func(c *Config) {
+		c.Title = t
+	}
+}
+
+// Window sets the initial size of the game window, in screen pixels.
+func Window(width, height int32) Option {
+	return func(c *Config) {
+		c.WindowSize = [2]int32{width, height}
+	}
+}
+
+// Screen sets the size of the virtual pixel screen.
+func Screen(width, height int16) Option {
+	return func(c *Config) {
+		c.ScreenSize = [2]int16{width, height}
+	}
+}
+
+// PixelSize sets the size of the virtual pixels, in screen pixels.
+func PixelSize(s int32) Option {
+	return func(c *Config) {
+		c.PixelSize = s
+	}
+}
+
+// ScreenMode sets the way the virtual screen is adapted to the window: "Fit",
+// "Extend", "Zoom" or "Fixed".
+func ScreenMode(m string) Option {
+	return func(c *Config) {
+		c.ScreenMode = m
+	}
+}
+
+// Fullscreen sets the initial fullscreen state, and the fullscreen mode
+// ("Desktop" or "Exclusive").
+func Fullscreen(f bool, mode string) Option {
+	return func(c *Config) {
+		c.Fullscreen = f
+		c.FullscreenMode = mode
+	}
+}
+
+// Display selects the display where the window is opened.
+func Display(d int) Option {
+	return func(c *Config) {
+		c.Display = d
+	}
+}
+
+// VSync enables or disables vertical synchronization.
+func VSync(v bool) Option {
+	return func(c *Config) {
+		c.VSync = v
+	}
+}
+
+// Multisample sets the number of samples used for multisample antialiasing (0
+// to disable it).
+func Multisample(s int) Option {
+	return func(c *Config) {
+		c.Multisample = s
+	}
+}
+
+// PaletteAuto enables or disables the automatic creation of palette colors when
+// loading indexed pictures.
+func PaletteAuto(a bool) Option {
+	return func(c *Config) {
+		c.PaletteAuto = a
+	}
+}
+
+// Debug enables or disables debug mode.
+func Debug(d bool) Option {
+	return func(c *Config) {
+		c.Debug = d
+	}
+}
+
+// CollectErrors changes the sticky errors of packages pixel and gl: when
+// enabled, their Err function returns all errors since the previous call,
+// instead of only the first one. Only the errors of the latest frame are kept.
+func CollectErrors(e bool) Option {
+	return func(c *Config) {
+		c.CollectErrors = e
+	}
+}
+
+// TouchMouse enables or disables the synthesis of mouse events from touch
+// events: the first finger on the screen acts as the left mouse button.
+func TouchMouse(t bool) Option {
+	return func(c *Config) {
+		c.TouchMouse = t
+	}
+}
+
+// ConfigFile changes the name of the configuration file, relative to the
+// executable path. An empty name disables the configuration file.
+func ConfigFile(name string) Option {
+	return func(*Config) {
+		internal.ConfigFile = name
+	}
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,50 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package carol_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/drakmaniso/carol"
+)
+
+//------------------------------------------------------------------------------
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		option carol.Option
+		err    string
+	}{
+		{carol.Title("Test"), ""},
+		{carol.ScreenMode("Zoom"), ""},
+		{carol.ScreenMode("Stretch"), `unknown ScreenMode "Stretch"`},
+		{carol.PixelSize(0), "invalid PixelSize 0"},
+		{
+			func(c *carol.Config) {
+				c.ScreenMode = "Fixed"
+				c.PixelSize = 8
+			},
+			"PixelSize 8 is too big",
+		},
+		{carol.Fullscreen(true, "Borderless"), `unknown FullscreenMode "Borderless"`},
+		{carol.Window(0, 600), "invalid WindowSize 0x600"},
+	}
+
+	for _, tt := range tests {
+		c := carol.GetConfig()
+		tt.option(&c)
+		err := c.Validate()
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("unexpected error: %s", err)
+		case tt.err != "" && err == nil:
+			t.Errorf("expected error %q, got nil", tt.err)
+		case tt.err != "" && !strings.Contains(err.Error(), tt.err):
+			t.Errorf("expected error %q, got %q", tt.err, err)
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
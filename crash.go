@@ -0,0 +1,148 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package carol
+
+//------------------------------------------------------------------------------
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/drakmaniso/carol/internal"
+)
+
+//------------------------------------------------------------------------------
+
+// A crashError is returned by Run after a panic. The user has already been
+// notified.
+type crashError struct {
+	cause  interface{}
+	report string
+}
+
+func (e crashError) Error() string {
+	if e.report == "" {
+		return fmt.Sprintf("panic: %v", e.cause)
+	}
+	return fmt.Sprintf("panic: %v (crash report saved to %s)", e.cause, e.report)
+}
+
+//------------------------------------------------------------------------------
+
+// recoverCrash is deferred by Run. In case of panic, it writes a crash report
+// in the user data directory, shows an error dialog (except in debug mode),
+// and replaces the error returned by Run.
+func recoverCrash(err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	stack := debug.Stack()
+
+	e := crashError{cause: r}
+	p, err2 := saveCrashReport(r, stack)
+	if err2 != nil {
+		internal.ErrorLog.Printf("unable to save crash report: %s", err2)
+	} else {
+		e.report = p
+	}
+	internal.ErrorLog.Printf("%s\n%s", e, stack)
+
+	if !internal.Config.Debug {
+		m := fmt.Sprintf("The game crashed: %v", r)
+		if e.report != "" {
+			m += "\n\nA crash report has been saved to:\n" + e.report
+		}
+		err2 := internal.ErrorDialog("%s", m)
+		if err2 != nil {
+			internal.ErrorLog.Printf("while opening dialog: %s", err2)
+		}
+	}
+
+	*err = e
+}
+
+func saveCrashReport(cause interface{}, stack []byte) (string, error) {
+	d, err := internal.UserDataDir()
+	if err != nil {
+		d = os.TempDir()
+	}
+	n := time.Now().Format("crash-20060102-150405.txt")
+	p := filepath.Join(d, n)
+
+	f, err := os.Create(p)
+	if err != nil {
+		return "", err
+	}
+	w := bufio.NewWriter(f)
+	writeCrashReport(w, cause, stack)
+	err = w.Flush()
+	if err2 := f.Close(); err == nil {
+		err = err2
+	}
+	if err != nil {
+		return "", err
+	}
+	return p, nil
+}
+
+// writeCrashReport writes everything useful to understand a crash: the stack
+// trace, the configuration, the OpenGL context, the frame statistics, and the
+// last events and log messages.
+func writeCrashReport(w io.Writer, cause interface{}, stack []byte) {
+	fmt.Fprintf(w, "%s crash report\n", internal.Config.Title)
+	fmt.Fprintf(w, "Time: %s\n", time.Now().Format(time.RFC3339))
+	fmt.Fprintf(w, "Go: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(w, "Panic: %v\n", cause)
+
+	fmt.Fprintf(w, "\n# Stack trace\n\n%s", stack)
+
+	fmt.Fprintf(w, "\n# Configuration\n\n")
+	c, err := json.MarshalIndent(internal.Config, "", "\t")
+	if err != nil {
+		fmt.Fprintf(w, "unavailable: %s\n", err)
+	} else {
+		fmt.Fprintf(w, "%s\n", c)
+	}
+
+	fmt.Fprintf(w, "\n# OpenGL\n\n%s\n", internal.OpenGLInfo)
+
+	fmt.Fprintf(w, "\n# Frame statistics\n\n")
+	fmt.Fprintf(w, "Time: %.3fs, Update steps: %d\n", Now(), loopTime.steps)
+	fmt.Fprintf(w, "Last frame: %.3fms\n", FrameTime()*1000)
+	a, o := FrameTimeAverage()
+	fmt.Fprintf(w, "Average frame: %.3fms, overruns: %d\n", a*1000, o)
+	if IsProfiling() {
+		s := LastFrameStats()
+		fmt.Fprintf(
+			w, "Profiled frame: %.3fms, GC: %d (%.3fms), allocations: %d (%d bytes)\n",
+			s.Duration*1000, s.GCCount, s.GCPause*1000, s.Allocs, s.AllocBytes,
+		)
+	}
+
+	e := internal.RecentEvents()
+	fmt.Fprintf(w, "\n# Last %d events\n\n", len(e))
+	for i := range e {
+		j, err := json.Marshal(e[i])
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(w, "%s\n", j)
+	}
+
+	l := internal.RecentLog()
+	fmt.Fprintf(w, "\n# Last %d log messages\n\n", len(l))
+	for _, m := range l {
+		fmt.Fprintf(w, "%s %s %s: %s\n", m.Time.Format("15:04:05.000000"), m.Level, m.System, m.Message)
+	}
+}
+
+//------------------------------------------------------------------------------
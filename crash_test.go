@@ -0,0 +1,81 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package carol
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/drakmaniso/carol/internal"
+)
+
+//------------------------------------------------------------------------------
+
+type crashLoop struct {
+	Handlers
+}
+
+func (crashLoop) Setup() error                   { return nil }
+func (crashLoop) Update() error                  { return nil }
+func (crashLoop) Draw(delta, lerp float64) error { return nil }
+
+func TestRecoverCrash(t *testing.T) {
+	d, err := ioutil.TempDir("", "carol-crash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev, set := os.LookupEnv("XDG_DATA_HOME")
+	os.Setenv("XDG_DATA_HOME", d)
+	loop := internal.Loop
+	internal.Config.Debug = true
+	t.Cleanup(func() {
+		if set {
+			os.Setenv("XDG_DATA_HOME", prev)
+		} else {
+			os.Unsetenv("XDG_DATA_HOME")
+		}
+		os.RemoveAll(d)
+		internal.Config.Debug = false
+		internal.Loop = loop
+	})
+
+	internal.Loop = crashLoop{}
+	internal.Dispatch(internal.Event{Kind: internal.EventKeyDown, Time: 1})
+
+	err = func() (err error) {
+		defer recoverCrash(&err)
+		var m map[string]int
+		m["boom"]++
+		return nil
+	}()
+
+	c, ok := err.(crashError)
+	if !ok {
+		t.Fatalf("got %v, want a crash error", err)
+	}
+	b, err := ioutil.ReadFile(c.report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := string(b)
+	for _, s := range []string{
+		"Panic: assignment to entry in nil map",
+		"# Stack trace",
+		"crash_test.go",
+		"# Configuration",
+		`"ScreenMode"`,
+		"# OpenGL",
+		"# Frame statistics",
+		`"Kind":"KeyDown"`,
+		"# Last",
+	} {
+		if !strings.Contains(r, s) {
+			t.Errorf("crash report is missing %q", s)
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
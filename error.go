@@ -6,6 +6,8 @@ package carol
 //------------------------------------------------------------------------------
 
 import (
+	"errors"
+
 	"github.com/drakmaniso/carol/internal"
 )
 
@@ -19,21 +21,29 @@ func Error(context string, err error) error {
 	return internal.WrappedError{context, err}
 }
 
-// ShowError shows an error to the user. In debug mode, it only prints to the
-// standard error output, otherwise it also brings a dialog box.
+// A WrappedError adds a context to an error. The original error can be
+// inspected with errors.Is and errors.As.
+type WrappedError = internal.WrappedError
+
+// An ErrorList holds several errors, e.g. all the errors collected during a
+// frame (see CollectErrors). errors.Is and errors.As match any of them.
+type ErrorList = internal.ErrorList
+
+// ShowError shows an error to the user. In debug mode, it only writes to the
+// log, otherwise it also brings a dialog box.
 func ShowError(e error) {
-	internal.Log.Printf("ERROR: %s", e)
+	var c crashError
+	if errors.As(e, &c) {
+		// Already logged and shown by Run
+		return
+	}
+	internal.ErrorLog.Printf("%s", e)
 	if !internal.Config.Debug {
 		err2 := internal.ErrorDialog("ERROR: %s", e)
 		if err2 != nil {
-			internal.Log.Printf("ERROR opening dialog:\n%s", err2)
+			internal.ErrorLog.Printf("while opening dialog: %s", err2)
 		}
 	}
 }
 
-// Log logs a formated message.
-func Log(format string, v ...interface{}) {
-	internal.Log.Printf(format, v...)
-}
-
 //------------------------------------------------------------------------------
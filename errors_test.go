@@ -0,0 +1,67 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package carol_test
+
+import (
+	"errors"
+	"io/fs"
+	"io/ioutil"
+	"testing"
+
+	"github.com/drakmaniso/carol"
+	"github.com/drakmaniso/carol/internal"
+	"github.com/drakmaniso/carol/pixel"
+)
+
+//------------------------------------------------------------------------------
+
+func TestErrors(t *testing.T) {
+	o := internal.LogOutput
+	internal.LogOutput = ioutil.Discard
+	defer func() {
+		internal.LogOutput = o
+		internal.Config.CollectErrors = false
+	}()
+
+	err := carol.Error("in game", carol.Error("loading", fs.ErrNotExist))
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("errors.Is through wrapping: got false for %v", err)
+	}
+	var w carol.WrappedError
+	if !errors.As(err, &w) || w.Context != "in game" {
+		t.Errorf("errors.As: got %#v", w)
+	}
+
+	pixel.GetPicture("missing")
+	pixel.GetColor("missing")
+	err = pixel.Err()
+	if !errors.Is(err, pixel.ErrPictureNotFound) || errors.Is(err, pixel.ErrColorNotFound) {
+		t.Errorf("sticky error: got %v, want only the first one", err)
+	}
+	if pixel.Err() != nil {
+		t.Errorf("sticky error not cleared")
+	}
+
+	internal.Config.CollectErrors = true
+	pixel.GetPicture("missing")
+	pixel.GetColor("missing")
+	err = pixel.Err()
+	if !errors.Is(err, pixel.ErrPictureNotFound) || !errors.Is(err, pixel.ErrColorNotFound) {
+		t.Errorf("collected errors: got %v", err)
+	}
+	var l carol.ErrorList
+	if !errors.As(err, &l) || len(l) != 2 {
+		t.Errorf("collected errors: got %#v", err)
+	}
+
+	pixel.GetPicture("missing")
+	internal.FrameNumber++
+	pixel.GetColor("missing")
+	err = pixel.Err()
+	if errors.Is(err, pixel.ErrPictureNotFound) || !errors.Is(err, pixel.ErrColorNotFound) {
+		t.Errorf("collected errors of the latest frame: got %v", err)
+	}
+}
+
+//------------------------------------------------------------------------------
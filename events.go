@@ -0,0 +1,110 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package carol
+
+//------------------------------------------------------------------------------
+
+import (
+	"github.com/drakmaniso/carol/internal"
+)
+
+//------------------------------------------------------------------------------
+
+// An Event is a copy of an SDL event, independent from SDL. It is used to feed
+// the game loop in headless runs.
+//
+// Only the fields relevant to the kind of event are used:
+//
+// - KeyDown and KeyUp use Label and Position;
+//
+// - MouseMotion uses DX and DY for the relative motion, X and Y for the
+// position, and Buttons for the state of all buttons;
+//
+// - MouseButtonDown and MouseButtonUp use Button and Clicks;
+//
+// - MouseWheel uses DX and DY;
+//
+// - WindowResized uses X and Y for the new size;
+//
+// - all gamepad events use Gamepad for the slot of the device; in addition,
+// GamepadButtonDown and GamepadButtonUp use PadButton, and GamepadAxisMotion
+// uses PadAxis and Value;
+//
+// - TextInput uses Text, and TextEditing uses Text, Start and Length;
+//
+// - FileDropped uses Text for the path of the file, and TextDropped for the
+// text;
+//
+// - all touch events use Finger, TouchX and TouchY for the normalized position,
+// and Pressure; TouchMove also uses TouchDX and TouchDY.
+type Event = internal.Event
+
+// An EventKind identifies the type of an Event.
+type EventKind = internal.EventKind
+
+// The kinds of events handled by the game loop.
+const (
+	EventQuit              = internal.EventQuit
+	EventWindowShown       = internal.EventWindowShown
+	EventWindowHidden      = internal.EventWindowHidden
+	EventWindowResized     = internal.EventWindowResized
+	EventWindowMinimized   = internal.EventWindowMinimized
+	EventWindowMaximized   = internal.EventWindowMaximized
+	EventWindowRestored    = internal.EventWindowRestored
+	EventWindowMouseEnter  = internal.EventWindowMouseEnter
+	EventWindowMouseLeave  = internal.EventWindowMouseLeave
+	EventWindowFocusGained = internal.EventWindowFocusGained
+	EventWindowFocusLost   = internal.EventWindowFocusLost
+	EventKeyDown           = internal.EventKeyDown
+	EventKeyUp             = internal.EventKeyUp
+	EventMouseMotion       = internal.EventMouseMotion
+	EventMouseButtonDown   = internal.EventMouseButtonDown
+	EventMouseButtonUp     = internal.EventMouseButtonUp
+	EventMouseWheel        = internal.EventMouseWheel
+
+	EventGamepadConnected    = internal.EventGamepadConnected
+	EventGamepadDisconnected = internal.EventGamepadDisconnected
+	EventGamepadButtonDown   = internal.EventGamepadButtonDown
+	EventGamepadButtonUp     = internal.EventGamepadButtonUp
+	EventGamepadAxisMotion   = internal.EventGamepadAxisMotion
+
+	EventTextInput   = internal.EventTextInput
+	EventTextEditing = internal.EventTextEditing
+
+	EventFileDropped = internal.EventFileDropped
+	EventTextDropped = internal.EventTextDropped
+
+	EventTouchDown = internal.EventTouchDown
+	EventTouchMove = internal.EventTouchMove
+	EventTouchUp   = internal.EventTouchUp
+)
+
+//------------------------------------------------------------------------------
+
+// An EventSource provides the events of a headless run, in place of the SDL
+// event queue. Its Events method is called once per frame with the current
+// time, and returns the events to dispatch before the updates of that frame.
+type EventSource interface {
+	Events(now float64) []Event
+}
+
+//------------------------------------------------------------------------------
+
+// A Script is an EventSource that delivers each event once the clock reaches
+// its time stamp. The events must be sorted by time.
+type Script []Event
+
+// Events returns (and removes from the script) all the events whose time stamp
+// is not after now.
+func (s *Script) Events(now float64) []Event {
+	i := 0
+	for i < len(*s) && (*s)[i].Time <= now {
+		i++
+	}
+	e := (*s)[:i]
+	*s = (*s)[i:]
+	return e
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,51 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package gamepad
+
+//------------------------------------------------------------------------------
+
+import (
+	"github.com/drakmaniso/carol/internal"
+)
+
+//------------------------------------------------------------------------------
+
+// A Button on a gamepad, in the standard controller mapping.
+type Button = internal.GamepadButton
+
+// Button constants
+const (
+	A             Button = 0
+	B             Button = 1
+	X             Button = 2
+	Y             Button = 3
+	Back          Button = 4
+	Guide         Button = 5
+	Start         Button = 6
+	LeftStick     Button = 7
+	RightStick    Button = 8
+	LeftShoulder  Button = 9
+	RightShoulder Button = 10
+	DpadUp        Button = 11
+	DpadDown      Button = 12
+	DpadLeft      Button = 13
+	DpadRight     Button = 14
+)
+
+//------------------------------------------------------------------------------
+
+// An Axis on a gamepad, in the standard controller mapping.
+type Axis = internal.GamepadAxis
+
+// Axis constants
+const (
+	LeftX        Axis = 0
+	LeftY        Axis = 1
+	RightX       Axis = 2
+	RightY       Axis = 3
+	TriggerLeft  Axis = 4
+	TriggerRight Axis = 5
+)
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,11 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+/*
+Package gamepad provides game controller support.
+
+Buttons and axes follow SDL's standard controller mapping (i.e. the layout of
+an Xbox controller). Each connected gamepad is assigned a slot, from 0 to
+Max-1, which identifies it until disconnection.
+*/
+package gamepad
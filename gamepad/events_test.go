@@ -0,0 +1,124 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package gamepad_test
+
+import (
+	"testing"
+
+	"github.com/drakmaniso/carol"
+	"github.com/drakmaniso/carol/gamepad"
+	"github.com/drakmaniso/carol/internal"
+)
+
+//------------------------------------------------------------------------------
+
+type padLoop struct {
+	carol.Handlers
+	connected, disconnected int
+	pad                     int
+	downs, ups              []gamepad.Button
+	axis                    float32
+}
+
+func (l *padLoop) Setup() error                                       { return nil }
+func (l *padLoop) Update() error                                      { return nil }
+func (l *padLoop) Draw(_, _ float64) error                            { return nil }
+func (l *padLoop) GamepadConnected(pad int)                           { l.connected++; l.pad = pad }
+func (l *padLoop) GamepadDisconnected(pad int)                        { l.disconnected++ }
+func (l *padLoop) GamepadButtonDown(pad int, b gamepad.Button)        { l.downs = append(l.downs, b) }
+func (l *padLoop) GamepadButtonUp(pad int, b gamepad.Button)          { l.ups = append(l.ups, b) }
+func (l *padLoop) GamepadAxisMotion(_ int, _ gamepad.Axis, v float32) { l.axis = v }
+
+//------------------------------------------------------------------------------
+
+func TestInvalidEvents(t *testing.T) {
+	script := carol.Script{
+		{Kind: carol.EventGamepadConnected, Time: 0, Gamepad: 1},
+		{Kind: carol.EventGamepadConnected, Time: 0, Gamepad: gamepad.Max},
+		{Kind: carol.EventGamepadButtonDown, Time: 0, Gamepad: -1, PadButton: gamepad.A},
+		{Kind: carol.EventGamepadButtonDown, Time: 0, Gamepad: 1, PadButton: 200},
+		{Kind: carol.EventGamepadAxisMotion, Time: 0, Gamepad: 1, PadAxis: 200, Value: 1000},
+		{Kind: carol.EventGamepadButtonDown, Time: 0, Gamepad: 1, PadButton: gamepad.B},
+	}
+
+	var l padLoop
+	s, err := carol.Simulate(&l, &script, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	err = s.Step(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.connected != 1 || len(l.downs) != 1 {
+		t.Errorf("got %d connections and %d button presses, expected 1 of each", l.connected, len(l.downs))
+	}
+	if !gamepad.IsConnected(1) || !gamepad.IsPressed(1, gamepad.B) {
+		t.Errorf("gamepad state not updated by valid events")
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestVirtual(t *testing.T) {
+	var l padLoop
+	s, err := carol.Simulate(&l, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	v, err := gamepad.AttachVirtual()
+	if err != nil {
+		t.Skip("virtual gamepads not available:", err)
+	}
+	// Dispatch the SDL events until the condition is met
+	pump := func(cond func() bool) bool {
+		for i := 0; i < 100 && !cond(); i++ {
+			internal.ProcessEvents()
+		}
+		return cond()
+	}
+
+	if !pump(func() bool { return l.connected == 1 }) {
+		v.Detach()
+		t.Fatal("virtual gamepad not connected")
+	}
+	pad := l.pad
+
+	err = v.SetButton(gamepad.X, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pump(func() bool { return len(l.downs) == 1 }) || l.downs[0] != gamepad.X || !gamepad.IsPressed(pad, gamepad.X) {
+		t.Errorf("button down: got %v", l.downs)
+	}
+	err = v.SetButton(gamepad.X, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pump(func() bool { return len(l.ups) == 1 }) || gamepad.IsPressed(pad, gamepad.X) {
+		t.Errorf("button up: got %v", l.ups)
+	}
+
+	err = v.SetAxis(gamepad.LeftX, 32767)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pump(func() bool { return l.axis == 1 }) || gamepad.Value(pad, gamepad.LeftX) != 1 {
+		t.Errorf("axis motion: got %g", l.axis)
+	}
+
+	err = v.Detach()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pump(func() bool { return l.disconnected == 1 }) || gamepad.IsConnected(pad) {
+		t.Errorf("virtual gamepad not disconnected")
+	}
+}
+
+//------------------------------------------------------------------------------
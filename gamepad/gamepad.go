@@ -0,0 +1,129 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package gamepad
+
+//------------------------------------------------------------------------------
+
+import (
+	"github.com/drakmaniso/carol/internal"
+	"github.com/drakmaniso/carol/plane"
+	"github.com/drakmaniso/carol/x/math32"
+)
+
+//------------------------------------------------------------------------------
+
+// Max is the maximum number of gamepads connected at the same time.
+const Max = internal.GamepadMax
+
+//------------------------------------------------------------------------------
+
+// IsConnected returns true if a gamepad is connected in the slot.
+func IsConnected(pad int) bool {
+	return valid(pad) && internal.Gamepads[pad].Connected
+}
+
+// Name returns the name of the gamepad connected in the slot.
+func Name(pad int) string {
+	if !IsConnected(pad) {
+		return ""
+	}
+	return internal.Gamepads[pad].Name
+}
+
+// IsPressed returns true if the button is currently held down.
+func IsPressed(pad int, b Button) bool {
+	return valid(pad) && int(b) < internal.GamepadButtonsMax &&
+		internal.Gamepads[pad].Buttons[b]
+}
+
+// Value returns the current position of an axis, between -1 and 1 for the
+// sticks, and between 0 and 1 for the triggers. The dead zone is applied
+// independently to each axis; use LeftStickPosition or RightStickPosition for
+// a radial dead zone.
+func Value(pad int, a Axis) float32 {
+	if !valid(pad) || int(a) >= internal.GamepadAxesMax {
+		return 0
+	}
+	return deadZone(raw(pad, a), deadzone)
+}
+
+// LeftStickPosition returns the position of the left stick, with a radial dead
+// zone.
+func LeftStickPosition(pad int) plane.Coord {
+	if !valid(pad) {
+		return plane.Coord{}
+	}
+	return radialDeadZone(plane.Coord{X: raw(pad, LeftX), Y: raw(pad, LeftY)}, deadzone)
+}
+
+// RightStickPosition returns the position of the right stick, with a radial
+// dead zone.
+func RightStickPosition(pad int) plane.Coord {
+	if !valid(pad) {
+		return plane.Coord{}
+	}
+	return radialDeadZone(plane.Coord{X: raw(pad, RightX), Y: raw(pad, RightY)}, deadzone)
+}
+
+func valid(pad int) bool {
+	return pad >= 0 && pad < internal.GamepadMax
+}
+
+func raw(pad int, a Axis) float32 {
+	return float32(internal.Gamepads[pad].Axes[a]) / 32767
+}
+
+//------------------------------------------------------------------------------
+
+// SetDeadZone changes the dead zone of all axes, as a fraction of the full
+// range (the default is 0.25).
+func SetDeadZone(d float32) {
+	deadzone = d
+}
+
+var deadzone = float32(0.25)
+
+// deadZone returns 0 if |v| is below d, and otherwise rescales v so that the
+// output range starts at the edge of the dead zone.
+func deadZone(v, d float32) float32 {
+	a := math32.Abs(v)
+	if a <= d {
+		return 0
+	}
+	if a > 1 {
+		a = 1
+	}
+	return math32.Copysign((a-d)/(1-d), v)
+}
+
+// radialDeadZone applies a dead zone to the length of a stick position,
+// preserving its direction.
+func radialDeadZone(v plane.Coord, d float32) plane.Coord {
+	l := v.Length()
+	if l <= d {
+		return plane.Coord{}
+	}
+	return v.Times(deadZone(l, d) / l)
+}
+
+//------------------------------------------------------------------------------
+
+// Rumble starts a rumble effect, with intensities between 0 and 1 for the
+// low and high frequency motors, and a duration in seconds.
+func Rumble(pad int, low, high float32, duration float64) error {
+	return internal.GamepadRumble(pad, low, high, duration)
+}
+
+//------------------------------------------------------------------------------
+
+// A Virtual gamepad is simulated by SDL's virtual joystick API. It is reported
+// to the game loop like a real device, which is useful for testing.
+type Virtual = internal.VirtualGamepad
+
+// AttachVirtual creates a new virtual gamepad.
+func AttachVirtual() (*Virtual, error) {
+	return internal.GamepadAttachVirtual()
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,46 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package gamepad
+
+import (
+	"testing"
+
+	"github.com/drakmaniso/carol/plane"
+	"github.com/drakmaniso/carol/x/math32"
+)
+
+//------------------------------------------------------------------------------
+
+func TestDeadZone(t *testing.T) {
+	tests := []struct {
+		in, out float32
+	}{
+		{0, 0},
+		{0.1, 0},
+		{0.25, 0},
+		{-0.2, 0},
+		{1, 1},
+		{-1, -1},
+		{0.625, 0.5},
+		{-0.625, -0.5},
+		{1.5, 1},
+	}
+	for _, tt := range tests {
+		if o := deadZone(tt.in, 0.25); !math32.IsNearlyEqual(o, tt.out, 1e-6) {
+			t.Errorf("deadZone(%v, 0.25) == %v, expected %v", tt.in, o, tt.out)
+		}
+	}
+}
+
+func TestRadialDeadZone(t *testing.T) {
+	if o := radialDeadZone(plane.Coord{X: 0.15, Y: 0.15}, 0.25); o != (plane.Coord{}) {
+		t.Errorf("expected zero inside the dead zone, got %v", o)
+	}
+	o := radialDeadZone(plane.Coord{X: 0.6, Y: 0.8}, 0.25)
+	if !o.IsNearlyEqual(plane.Coord{X: 0.6, Y: 0.8}, 1e-6) {
+		t.Errorf("expected unchanged position at full tilt, got %v", o)
+	}
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,221 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package carol
+
+//------------------------------------------------------------------------------
+
+import (
+	"github.com/drakmaniso/carol/internal"
+	"github.com/drakmaniso/carol/x/gl"
+)
+
+//------------------------------------------------------------------------------
+
+// A VirtualClock is a clock that only advances when told to. It is used to
+// drive the game loop in headless runs.
+//
+// Whole time steps are counted separately, so that a clock advanced one step
+// at a time stays exactly in line with the Update calls.
+type VirtualClock struct {
+	start float64
+	step  float64 // duration of the time steps counted
+	steps uint64  // number of whole time steps
+	extra float64 // sum of the other advances
+}
+
+// Seconds returns the current time of the clock.
+func (c *VirtualClock) Seconds() float64 {
+	return c.start + float64(c.steps)*c.step + c.extra
+}
+
+// Advance moves the clock forward.
+func (c *VirtualClock) Advance(seconds float64) {
+	c.extra += seconds
+}
+
+// tick moves the clock forward by exactly one time step.
+func (c *VirtualClock) tick() {
+	if c.step != timeStep {
+		c.start += float64(c.steps) * c.step
+		c.step, c.steps = timeStep, 0
+	}
+	c.steps++
+}
+
+// reset sets the clock to t.
+func (c *VirtualClock) reset(t float64) {
+	*c = VirtualClock{start: t}
+}
+
+//------------------------------------------------------------------------------
+
+// A Simulation runs a game loop without window, driven by a virtual clock and
+// a scripted event source. It is intended for automated tests:
+//
+//  s, err := carol.Simulate(&game, &script, false)
+//  if err != nil {
+//    t.Fatal(err)
+//  }
+//  defer s.Close()
+//  err = s.Step(60) // One second of game time
+//
+// Only one simulation (or game loop) can be active at any time.
+type Simulation struct {
+	clock     VirtualClock
+	source    EventSource
+	pending   []Event
+	offscreen bool
+}
+
+// Simulate prepares a headless run of loop, and calls its Setup method. The
+// events are taken from source, which can be nil.
+//
+// If offscreen is false, neither SDL nor OpenGL are initialized, and the
+// pixel package only keeps track of the screen and picture sizes. Otherwise,
+// a hidden window is opened, and the pixel screen is rendered to its
+// offscreen framebuffer.
+//
+// The configuration is built only from the default values and the options:
+// the configuration file, environment variables and command-line arguments
+// are ignored.
+func Simulate(loop GameLoop, source EventSource, offscreen bool, options ...Option) (*Simulation, error) {
+	s := &Simulation{
+		source:    source,
+		offscreen: offscreen,
+	}
+
+	internal.Config = internal.DefaultConfig
+	for _, o := range options {
+		o(&internal.Config)
+	}
+	err := internal.Config.Validate()
+	if err != nil {
+		return nil, internal.Error("in configuration", err)
+	}
+
+	internal.Loop = loop
+	internal.QuitRequested = false
+	internal.Headless = !offscreen
+	internal.Window.Width = internal.Config.WindowSize[0]
+	internal.Window.Height = internal.Config.WindowSize[1]
+
+	if offscreen {
+		err = internal.SetupOffscreen()
+		if err != nil {
+			s.Close()
+			return nil, internal.Error("in offscreen setup", err)
+		}
+
+		err = gl.Setup(internal.Config.Debug)
+		if err != nil {
+			s.Close()
+			return nil, internal.Error("in OpenGL setup", err)
+		}
+		gl.SetLogger(internal.Logger{System: "gl", Level: internal.DebugLevel})
+	}
+
+	err = internal.PixelSetup()
+	if err != nil {
+		s.Close()
+		return nil, internal.Error("in pixel Setup", err)
+	}
+
+	err = internal.TextSetup()
+	if err != nil {
+		s.Close()
+		return nil, internal.Error("in text Setup", err)
+	}
+
+	err = internal.AudioSetup()
+	if err != nil {
+		s.Close()
+		return nil, internal.Error("in audio Setup", err)
+	}
+
+	err = internal.Loop.Setup()
+	if err != nil {
+		s.Close()
+		return nil, internal.Error("in game loop Setup", err)
+	}
+
+	internal.Loop.WindowResized(internal.Window.Width, internal.Window.Height)
+	internal.ResizeScreen()
+
+	if r, ok := source.(*Recording); ok {
+		// Replay with the same timing as the recording
+		r.Rewind()
+		s.clock.reset(r.Start)
+		timeStep = r.TimeStep
+	}
+	resetLoopTime(s.clock.Seconds())
+
+	if session.record != nil {
+		startRecording(session.record)
+		session.record = nil
+	}
+
+	return s, nil
+}
+
+//------------------------------------------------------------------------------
+
+// Step runs n frames, each one lasting exactly one time step (i.e. there is
+// exactly one call to Update per frame). It stops early if the game loop
+// requested to quit.
+func (s *Simulation) Step(n int) error {
+	for i := 0; i < n && !internal.QuitRequested; i++ {
+		s.clock.tick()
+		err := frame(s.clock.Seconds(), s.dispatch)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Frame advances the virtual clock, and runs one frame of the game loop.
+func (s *Simulation) Frame(delta float64) error {
+	s.clock.Advance(delta)
+	return frame(s.clock.Seconds(), s.dispatch)
+}
+
+func (s *Simulation) dispatch() {
+	if s.source != nil {
+		s.pending = append(s.pending, s.source.Events(s.clock.Seconds())...)
+	}
+	for i := 0; i < len(s.pending) && !internal.QuitRequested; i++ {
+		internal.Dispatch(s.pending[i])
+	}
+	s.pending = s.pending[:0]
+}
+
+// Send queues events to be dispatched at the start of the next frame, in
+// addition to those of the event source.
+func (s *Simulation) Send(events ...Event) {
+	s.pending = append(s.pending, events...)
+}
+
+// Clock returns the virtual clock of the simulation.
+func (s *Simulation) Clock() *VirtualClock {
+	return &s.clock
+}
+
+// Close ends the simulation, and releases the hidden window if any. It returns
+// the first error that occured while recording (see Record), if any.
+func (s *Simulation) Close() error {
+	err := stopRecording()
+	internal.ScriptQuit()
+	internal.TweenQuit()
+	internal.AudioQuit()
+	if s.offscreen {
+		internal.DestroyWindow()
+		internal.SDLQuit()
+		internal.Offscreen = false
+	}
+	internal.Headless = false
+	internal.QuitRequested = false
+	return err
+}
+
+//------------------------------------------------------------------------------
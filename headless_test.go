@@ -0,0 +1,92 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package carol_test
+
+import (
+	"testing"
+
+	"github.com/drakmaniso/carol"
+	"github.com/drakmaniso/carol/key"
+)
+
+//------------------------------------------------------------------------------
+
+type countingLoop struct {
+	carol.Handlers
+	updates, draws, jumps int
+}
+
+func (l *countingLoop) Setup() error { return nil }
+
+func (l *countingLoop) Update() error {
+	l.updates++
+	if key.IsPressed(key.PositionSpace) {
+		l.jumps++
+	}
+	return nil
+}
+
+func (l *countingLoop) Draw(_, _ float64) error {
+	l.draws++
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+func TestSimulation(t *testing.T) {
+	ts := carol.TimeStep()
+	script := carol.Script{
+		{Kind: carol.EventKeyDown, Time: 10 * ts, Label: key.LabelSpace, Position: key.PositionSpace},
+		{Kind: carol.EventKeyUp, Time: 15 * ts, Label: key.LabelSpace, Position: key.PositionSpace},
+	}
+
+	var l countingLoop
+	s, err := carol.Simulate(&l, &script, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	err = s.Step(60)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if l.updates != 60 || l.draws != 60 {
+		t.Errorf("got %d updates and %d draws, expected 60 of each", l.updates, l.draws)
+	}
+	if l.jumps != 5 {
+		t.Errorf("key was pressed during %d updates, expected 5", l.jumps)
+	}
+	if len(script) != 0 {
+		t.Errorf("%d events left in script", len(script))
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestSimulationSteps(t *testing.T) {
+	defer carol.SetTimeStep(carol.TimeStep())
+	for _, ts := range []float64{1.0 / 60, 1.0 / 50, 1.0 / 30, 1.0 / 120} {
+		carol.SetTimeStep(ts)
+		var l countingLoop
+		s, err := carol.Simulate(&l, nil, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 1; i <= 1000; i++ {
+			err = s.Step(1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if l.updates != i {
+				t.Errorf("time step %g: %d updates after %d frames", ts, l.updates, i)
+				break
+			}
+		}
+		s.Close()
+	}
+}
+
+//------------------------------------------------------------------------------
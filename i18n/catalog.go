@@ -0,0 +1,266 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package i18n
+
+//------------------------------------------------------------------------------
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/drakmaniso/carol/internal"
+)
+
+//------------------------------------------------------------------------------
+
+// A catalog holds the messages of one locale.
+type catalog struct {
+	lang     string
+	messages map[string]message
+	// pluralForm, if not nil, gives the index of the plural forms (for PO
+	// files)
+	pluralForm func(n int) int
+}
+
+// A message is either a simple text, or a set of plural variants, indexed by
+// CLDR category (JSON files) or by form (PO files).
+type message struct {
+	text       string
+	categories map[string]string
+	forms      []string
+}
+
+// plural returns the variant of the message for a count.
+func (c *catalog) plural(m message, n int) string {
+	switch {
+	case m.forms != nil:
+		i := b2i(n != 1)
+		if c.pluralForm != nil {
+			i = c.pluralForm(n)
+		}
+		if i >= 0 && i < len(m.forms) && m.forms[i] != "" {
+			return m.forms[i]
+		}
+		return m.forms[len(m.forms)-1]
+	case m.categories != nil:
+		t, ok := m.categories[pluralCategory(c.lang, n)]
+		if !ok {
+			t = m.categories["other"]
+		}
+		return t
+	}
+	return m.text
+}
+
+//------------------------------------------------------------------------------
+
+// loadCatalog reads the catalog of a locale from the asset file system. It
+// returns nil if there is none.
+func loadCatalog(locale string) (*catalog, error) {
+	c := catalog{
+		lang:     language(locale),
+		messages: map[string]message{},
+	}
+
+	for _, ext := range []string{".json", ".po"} {
+		n := Dir + "/" + locale + ext
+		f, err := internal.Assets.Open(n)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			continue
+		case err != nil:
+			return nil, internal.Error(`while opening catalog "`+n+`"`, err)
+		}
+		if ext == ".json" {
+			err = c.readJSON(f)
+		} else {
+			err = c.readPO(f)
+		}
+		f.Close()
+		if err != nil {
+			return nil, internal.Error(`in catalog "`+n+`"`, err)
+		}
+		return &c, nil
+	}
+
+	return nil, nil
+}
+
+//------------------------------------------------------------------------------
+
+// readJSON reads a catalog in JSON format: an object whose values are either
+// strings or, for plurals, objects indexed by CLDR category.
+func (c *catalog) readJSON(r io.Reader) error {
+	var j map[string]json.RawMessage
+	err := json.NewDecoder(r).Decode(&j)
+	if err != nil {
+		return err
+	}
+
+	for k, v := range j {
+		var m message
+		if json.Unmarshal(v, &m.text) != nil {
+			err := json.Unmarshal(v, &m.categories)
+			if err != nil {
+				return fmt.Errorf("invalid message %q", k)
+			}
+			if _, ok := m.categories["other"]; !ok {
+				return fmt.Errorf(`message %q has no "other" plural`, k)
+			}
+		}
+		c.messages[k] = m
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// readPO reads a catalog in the gettext PO format. The msgid is used as key;
+// untranslated and fuzzy entries are ignored.
+func (c *catalog) readPO(r io.Reader) error {
+	var (
+		id, plural, str string
+		forms           []string
+		fuzzy, context  bool
+		translated      bool
+		field           *string
+	)
+
+	flush := func() error {
+		defer func() {
+			id, plural, str, forms = "", "", "", nil
+			fuzzy, context, translated, field = false, false, false, nil
+		}()
+		switch {
+		case field == nil || context:
+			return nil
+		case id == "":
+			return c.readPOHeader(str)
+		case fuzzy:
+			return nil
+		case plural != "":
+			for _, f := range forms {
+				if f != "" {
+					c.messages[id] = message{forms: forms}
+					break
+				}
+			}
+		case str != "":
+			c.messages[id] = message{text: str}
+		}
+		return nil
+	}
+
+	s := bufio.NewScanner(r)
+	line := 0
+	for s.Scan() {
+		line++
+		l := strings.TrimSpace(s.Text())
+
+		switch {
+		case l == "":
+			err := flush()
+			if err != nil {
+				return err
+			}
+			continue
+		case strings.HasPrefix(l, "#,"):
+			fuzzy = fuzzy || strings.Contains(l, "fuzzy")
+			continue
+		case strings.HasPrefix(l, "#"):
+			continue
+		case strings.HasPrefix(l, `"`):
+			if field == nil {
+				return fmt.Errorf("line %d: unexpected string", line)
+			}
+			v, err := strconv.Unquote(l)
+			if err != nil {
+				return fmt.Errorf("line %d: %s", line, err)
+			}
+			*field += v
+			continue
+		}
+
+		i := strings.IndexByte(l, ' ')
+		if i < 0 {
+			return fmt.Errorf("line %d: syntax error", line)
+		}
+		k := l[:i]
+		v, err := strconv.Unquote(strings.TrimSpace(l[i+1:]))
+		if err != nil {
+			return fmt.Errorf("line %d: %s", line, err)
+		}
+
+		switch {
+		case k == "msgctxt" || k == "msgid":
+			// Start of a new entry
+			if translated {
+				err := flush()
+				if err != nil {
+					return err
+				}
+			}
+			if k == "msgctxt" {
+				// Messages with context are not supported
+				context = true
+				field = new(string)
+				break
+			}
+			id = v
+			field = &id
+		case k == "msgid_plural":
+			plural = v
+			field = &plural
+		case k == "msgstr":
+			str = v
+			field = &str
+			translated = true
+		case strings.HasPrefix(k, "msgstr[") && strings.HasSuffix(k, "]"):
+			n, err := strconv.Atoi(k[len("msgstr[") : len(k)-1])
+			if err != nil || n < 0 || n > 16 {
+				return fmt.Errorf("line %d: invalid plural form %q", line, k)
+			}
+			for len(forms) <= n {
+				forms = append(forms, "")
+			}
+			forms[n] = v
+			field = &forms[n]
+			translated = true
+		default:
+			return fmt.Errorf("line %d: unknown keyword %q", line, k)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+	return flush()
+}
+
+// readPOHeader extracts the plural rule from the header of a PO file.
+func (c *catalog) readPOHeader(h string) error {
+	for _, l := range strings.Split(h, "\n") {
+		if !strings.HasPrefix(l, "Plural-Forms:") {
+			continue
+		}
+		i := strings.Index(l, "plural=")
+		if i < 0 {
+			return errors.New("invalid Plural-Forms header")
+		}
+		e := strings.TrimSuffix(strings.TrimSpace(l[i+len("plural="):]), ";")
+		f, err := parsePluralForms(e)
+		if err != nil {
+			return err
+		}
+		c.pluralForm = f
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
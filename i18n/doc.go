@@ -0,0 +1,27 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+/*
+Package i18n provides translated messages, loaded from per-locale catalogs.
+
+The catalogs are in the "locales" directory of the asset file system, and
+named after their locale (e.g. "fr.json" or "pt-BR.po"). JSON catalogs map each
+key to a message, or to its plural variants indexed by CLDR category:
+
+  {
+  	"greeting": "Bonjour, {name} !",
+  	"apples": {"one": "{count} pomme", "other": "{count} pommes"}
+  }
+
+Gettext PO files are also supported, with their Plural-Forms header. The msgid
+is then used as key.
+
+  title := i18n.T("title")
+  hello := i18n.Format("greeting", i18n.Args{"name": player.Name})
+  apples := i18n.Plural("apples", n, nil)
+
+At setup, the locale is chosen among the preferences of the user, unless
+SetLocale was called before. Missing messages fall back to the language of the
+locale, then to the default locale, and finally to the key itself.
+*/
+package i18n
@@ -0,0 +1,287 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package i18n
+
+//------------------------------------------------------------------------------
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/drakmaniso/carol/internal"
+)
+
+//------------------------------------------------------------------------------
+
+// Dir is the directory of the catalogs, in the asset file system.
+const Dir = "locales"
+
+var (
+	defaultLocale = "en"
+	current       string
+
+	// catalogs holds the loaded catalogs, by locale (nil when the locale has
+	// no catalog).
+	catalogs = map[string]*catalog{}
+
+	missingKeys = map[string]bool{}
+)
+
+// The loggers of the i18n subsystem.
+var (
+	debug   = internal.Logger{System: "i18n", Level: internal.DebugLevel}
+	warning = internal.Logger{System: "i18n", Level: internal.WarningLevel}
+)
+
+//------------------------------------------------------------------------------
+
+func init() {
+	internal.TextSetup = setupHook
+}
+
+func setupHook() error {
+	if current == "" {
+		current = defaultLocale
+		for _, l := range internal.SystemLocales() {
+			l = normalize(l)
+			c, err := getCatalog(l)
+			if err != nil {
+				return err
+			}
+			if c == nil {
+				c, err = getCatalog(language(l))
+				if err != nil {
+					return err
+				}
+			}
+			if c != nil {
+				current = l
+				break
+			}
+		}
+	}
+
+	debug.Printf("Locale: %s", current)
+	return SetLocale(current)
+}
+
+//------------------------------------------------------------------------------
+
+// SetDefaultLocale changes the locale used for the messages missing from the
+// current locale ("en" by default).
+func SetDefaultLocale(locale string) error {
+	defaultLocale = normalize(locale)
+	_, err := getCatalog(defaultLocale)
+	return err
+}
+
+// SetLocale changes the current locale. If there is no catalog for it, the
+// catalog of its language is used (e.g. "fr" for "fr-CA"), and then the one of
+// the default locale.
+//
+// If it's not called before Run, the locale is chosen among the preferences
+// of the user, according to the available catalogs.
+func SetLocale(locale string) error {
+	locale = normalize(locale)
+	for _, l := range chain(locale) {
+		_, err := getCatalog(l)
+		if err != nil {
+			return err
+		}
+	}
+	current = locale
+	return nil
+}
+
+// Locale returns the current locale.
+func Locale() string {
+	if current == "" {
+		return defaultLocale
+	}
+	return current
+}
+
+// Locales returns the locales of all the catalogs in the asset file system.
+func Locales() ([]string, error) {
+	d, err := fs.ReadDir(internal.Assets, Dir)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil, nil
+	case err != nil:
+		return nil, internal.Error("while listing catalogs", err)
+	}
+	var l []string
+	for _, e := range d {
+		x := path.Ext(e.Name())
+		if !e.IsDir() && (x == ".json" || x == ".po") {
+			l = append(l, strings.TrimSuffix(e.Name(), x))
+		}
+	}
+	return l, nil
+}
+
+// SystemLocales returns the locales preferred by the user, in order of
+// preference.
+func SystemLocales() []string {
+	l := internal.SystemLocales()
+	for i := range l {
+		l[i] = normalize(l[i])
+	}
+	return l
+}
+
+//------------------------------------------------------------------------------
+
+// Args holds the values of named placeholders.
+type Args map[string]interface{}
+
+// T returns the translation of a message in the current locale. If it's
+// missing, the message of the default locale is used, or the key itself.
+func T(key string) string {
+	m, c := lookup(key)
+	if c == nil {
+		return key
+	}
+	return c.plural(m, 0)
+}
+
+// Format returns the translation of a message, with all named placeholders
+// (e.g. "{name}") replaced by the corresponding argument. Unknown placeholders
+// are left as is, and "{{" is replaced by "{".
+func Format(key string, args Args) string {
+	m, c := lookup(key)
+	if c == nil {
+		return replace(key, args)
+	}
+	return replace(c.plural(m, 0), args)
+}
+
+// Plural returns the translation of a message, using the variant for count
+// according to the plural rules of the locale. The "{count}" placeholder is
+// also available.
+func Plural(key string, count int, args Args) string {
+	a := Args{"count": count}
+	for k, v := range args {
+		a[k] = v
+	}
+	m, c := lookup(key)
+	if c == nil {
+		return replace(key, a)
+	}
+	return replace(c.plural(m, count), a)
+}
+
+//------------------------------------------------------------------------------
+
+// lookup finds a message in the catalogs of the current locale and its
+// fallbacks.
+func lookup(key string) (message, *catalog) {
+	for _, l := range chain(Locale()) {
+		c := catalogs[l]
+		if c == nil {
+			continue
+		}
+		m, ok := c.messages[key]
+		if ok {
+			return m, c
+		}
+	}
+	if !missingKeys[key] {
+		missingKeys[key] = true
+		debug.Printf("Missing message %q in locale %s", key, Locale())
+	}
+	return message{}, nil
+}
+
+func getCatalog(locale string) (*catalog, error) {
+	c, ok := catalogs[locale]
+	if ok {
+		return c, nil
+	}
+	c, err := loadCatalog(locale)
+	if err != nil {
+		return nil, err
+	}
+	catalogs[locale] = c
+	return c, nil
+}
+
+// chain returns the locales to search for a message, in order.
+func chain(locale string) []string {
+	var l []string
+	for _, c := range []string{locale, language(locale), defaultLocale, language(defaultLocale)} {
+		dup := false
+		for i := range l {
+			dup = dup || l[i] == c
+		}
+		if !dup {
+			l = append(l, c)
+		}
+	}
+	return l
+}
+
+// normalize converts a locale to the form used for catalog names, e.g.
+// "fr_CA.UTF-8" to "fr-CA".
+func normalize(locale string) string {
+	if i := strings.IndexAny(locale, ".@"); i >= 0 {
+		locale = locale[:i]
+	}
+	p := strings.Split(strings.Replace(locale, "_", "-", -1), "-")
+	p[0] = strings.ToLower(p[0])
+	for i := 1; i < len(p); i++ {
+		if len(p[i]) == 2 {
+			p[i] = strings.ToUpper(p[i])
+		}
+	}
+	return strings.Join(p, "-")
+}
+
+// language returns the language part of a locale.
+func language(locale string) string {
+	if i := strings.IndexByte(locale, '-'); i >= 0 {
+		return locale[:i]
+	}
+	return locale
+}
+
+//------------------------------------------------------------------------------
+
+func replace(s string, args Args) string {
+	if !strings.Contains(s, "{") {
+		return s
+	}
+	var b strings.Builder
+	for {
+		i := strings.IndexByte(s, '{')
+		if i < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+		b.WriteString(s[:i])
+		s = s[i:]
+		if strings.HasPrefix(s, "{{") {
+			b.WriteByte('{')
+			s = s[2:]
+			continue
+		}
+		j := strings.IndexByte(s, '}')
+		if j < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+		v, ok := args[s[1:j]]
+		if ok {
+			fmt.Fprint(&b, v)
+		} else {
+			b.WriteString(s[:j+1])
+		}
+		s = s[j+1:]
+	}
+}
+
+//------------------------------------------------------------------------------
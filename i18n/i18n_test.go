@@ -0,0 +1,128 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package i18n
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/drakmaniso/carol"
+)
+
+//------------------------------------------------------------------------------
+
+const frPO = `# French translation
+msgid ""
+msgstr ""
+"Content-Type: text/plain; charset=UTF-8\n"
+"Plural-Forms: nplurals=2; plural=(n > 1);\n"
+
+msgid "Quit"
+msgstr "Quitter"
+
+#, fuzzy
+msgid "Options"
+msgstr "Paramètres"
+
+msgid "{count} life"
+msgid_plural "{count} lives"
+msgstr[0] "{count} vie"
+msgstr[1] "{count} vies"
+`
+
+const ruPO = `msgid ""
+msgstr "Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+msgid "{count} life"
+msgid_plural "{count} lives"
+msgstr[0] "{count} жизнь"
+msgstr[1] "{count} жизни"
+msgstr[2] "{count} жизней"
+`
+
+func setup(t *testing.T) {
+	carol.Mount(fstest.MapFS{
+		"locales/en.json": {Data: []byte(`{
+			"hello": "Hello, {name}!",
+			"apples": {"one": "{count} apple", "other": "{count} apples"},
+			"only-en": "English"
+		}`)},
+		"locales/fr.po": {Data: []byte(frPO)},
+		"locales/ru.po": {Data: []byte(ruPO)},
+		"locales/pl.json": {Data: []byte(`{
+			"apples": {"one": "{count} jabłko", "few": "{count} jabłka", "many": "{count} jabłek", "other": "{count} jabłka"}
+		}`)},
+	})
+	t.Cleanup(func() {
+		carol.UnmountAll()
+		catalogs = map[string]*catalog{}
+		current = ""
+	})
+}
+
+func TestMessages(t *testing.T) {
+	setup(t)
+
+	check := func(got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+
+	err := SetLocale("en_US.UTF-8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(Locale(), "en-US")
+	check(Format("hello", Args{"name": "Ada"}), "Hello, Ada!")
+	check(Format("hello", nil), "Hello, {name}!")
+	check(Plural("apples", 1, nil), "1 apple")
+	check(Plural("apples", 3, nil), "3 apples")
+	check(T("missing"), "missing")
+
+	err = SetLocale("fr-CA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(T("Quit"), "Quitter")
+	check(T("Options"), "Options")
+	check(T("only-en"), "English")
+	check(Plural("{count} life", 0, nil), "0 vie")
+	check(Plural("{count} life", 2, nil), "2 vies")
+
+	SetLocale("ru")
+	check(Plural("{count} life", 1, nil), "1 жизнь")
+	check(Plural("{count} life", 22, nil), "22 жизни")
+	check(Plural("{count} life", 11, nil), "11 жизней")
+
+	SetLocale("pl")
+	check(Plural("apples", 1, nil), "1 jabłko")
+	check(Plural("apples", 24, nil), "24 jabłka")
+	check(Plural("apples", 25, nil), "25 jabłek")
+
+	l, err := Locales()
+	if err != nil || len(l) != 4 {
+		t.Errorf("Locales: got %v, %v", l, err)
+	}
+}
+
+func TestPluralForms(t *testing.T) {
+	f, err := parsePluralForms("n==1 ? 0 : n==2 ? 1 : (n>=3 && n<=10) ? 2 : 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n, want := range map[int]int{0: 3, 1: 0, 2: 1, 5: 2, 11: 3} {
+		if got := f(n); got != want {
+			t.Errorf("n=%d: got %d, want %d", n, got, want)
+		}
+	}
+
+	_, err = parsePluralForms("(n != 1")
+	if err == nil {
+		t.Errorf("no error for unbalanced parenthesis")
+	}
+}
+
+//------------------------------------------------------------------------------
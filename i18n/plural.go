@@ -0,0 +1,257 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package i18n
+
+//------------------------------------------------------------------------------
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+
+// pluralCategory returns the CLDR plural category ("zero", "one", "two",
+// "few", "many" or "other") of an integer count, for a language.
+func pluralCategory(lang string, n int) string {
+	if n < 0 {
+		n = -n
+	}
+	switch lang {
+	case "ja", "zh", "ko", "vi", "th", "id", "ms":
+		return "other"
+	case "fr", "pt":
+		if n <= 1 {
+			return "one"
+		}
+	case "ru", "uk", "be":
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return "one"
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return "few"
+		}
+		return "many"
+	case "pl":
+		switch {
+		case n == 1:
+			return "one"
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return "few"
+		}
+		return "many"
+	case "cs", "sk":
+		switch {
+		case n == 1:
+			return "one"
+		case n >= 2 && n <= 4:
+			return "few"
+		}
+	case "ar":
+		switch {
+		case n == 0:
+			return "zero"
+		case n == 1:
+			return "one"
+		case n == 2:
+			return "two"
+		case n%100 >= 3 && n%100 <= 10:
+			return "few"
+		case n%100 >= 11:
+			return "many"
+		}
+	default:
+		if n == 1 {
+			return "one"
+		}
+	}
+	return "other"
+}
+
+//------------------------------------------------------------------------------
+
+// parsePluralForms parses the "plural" expression of a gettext Plural-Forms
+// header (e.g. "(n != 1)"), which uses the syntax of C expressions.
+func parsePluralForms(expr string) (func(n int) int, error) {
+	p := pluralParser{s: expr}
+	f, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	p.skip()
+	if p.i < len(p.s) {
+		return nil, fmt.Errorf("unexpected %q in plural expression", p.s[p.i:])
+	}
+	return f, nil
+}
+
+type pluralParser struct {
+	s string
+	i int
+}
+
+func (p *pluralParser) skip() {
+	for p.i < len(p.s) && (p.s[p.i] == ' ' || p.s[p.i] == '\t') {
+		p.i++
+	}
+}
+
+// accept consumes one of the operators, and returns it (or "" if none
+// matches).
+func (p *pluralParser) accept(ops ...string) string {
+	p.skip()
+	for _, o := range ops {
+		if strings.HasPrefix(p.s[p.i:], o) {
+			p.i += len(o)
+			return o
+		}
+	}
+	return ""
+}
+
+func b2i(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func (p *pluralParser) ternary() (func(int) int, error) {
+	c, err := p.binary(0)
+	if err != nil || p.accept("?") == "" {
+		return c, err
+	}
+	a, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	if p.accept(":") == "" {
+		return nil, fmt.Errorf("missing ':' in plural expression")
+	}
+	b, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	return func(n int) int {
+		if c(n) != 0 {
+			return a(n)
+		}
+		return b(n)
+	}, nil
+}
+
+// pluralOperators lists the binary operators, by increasing precedence. Longer
+// operators come first, so that "<=" is not parsed as "<".
+var pluralOperators = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<=", ">=", "<", ">"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *pluralParser) binary(level int) (func(int) int, error) {
+	if level == len(pluralOperators) {
+		return p.unary()
+	}
+	a, err := p.binary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		o := p.accept(pluralOperators[level]...)
+		if o == "" {
+			return a, nil
+		}
+		b, err := p.binary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		a = pluralOperation(o, a, b)
+	}
+}
+
+func pluralOperation(o string, a, b func(int) int) func(int) int {
+	switch o {
+	case "||":
+		return func(n int) int { return b2i(a(n) != 0 || b(n) != 0) }
+	case "&&":
+		return func(n int) int { return b2i(a(n) != 0 && b(n) != 0) }
+	case "==":
+		return func(n int) int { return b2i(a(n) == b(n)) }
+	case "!=":
+		return func(n int) int { return b2i(a(n) != b(n)) }
+	case "<=":
+		return func(n int) int { return b2i(a(n) <= b(n)) }
+	case ">=":
+		return func(n int) int { return b2i(a(n) >= b(n)) }
+	case "<":
+		return func(n int) int { return b2i(a(n) < b(n)) }
+	case ">":
+		return func(n int) int { return b2i(a(n) > b(n)) }
+	case "+":
+		return func(n int) int { return a(n) + b(n) }
+	case "-":
+		return func(n int) int { return a(n) - b(n) }
+	case "*":
+		return func(n int) int { return a(n) * b(n) }
+	case "/":
+		return func(n int) int {
+			if d := b(n); d != 0 {
+				return a(n) / d
+			}
+			return 0
+		}
+	default: // "%"
+		return func(n int) int {
+			if d := b(n); d != 0 {
+				return a(n) % d
+			}
+			return 0
+		}
+	}
+}
+
+func (p *pluralParser) unary() (func(int) int, error) {
+	if p.accept("!") != "" {
+		a, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(n int) int { return b2i(a(n) == 0) }, nil
+	}
+
+	if p.accept("(") != "" {
+		a, err := p.ternary()
+		if err != nil {
+			return nil, err
+		}
+		if p.accept(")") == "" {
+			return nil, fmt.Errorf("missing ')' in plural expression")
+		}
+		return a, nil
+	}
+
+	if p.accept("n") != "" {
+		return func(n int) int { return n }, nil
+	}
+
+	j := p.i
+	for j < len(p.s) && p.s[j] >= '0' && p.s[j] <= '9' {
+		j++
+	}
+	if j == p.i {
+		return nil, fmt.Errorf("unexpected %q in plural expression", p.s[p.i:])
+	}
+	v, err := strconv.Atoi(p.s[p.i:j])
+	if err != nil {
+		return nil, err
+	}
+	p.i = j
+	return func(int) int { return v }, nil
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,120 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package input
+
+//------------------------------------------------------------------------------
+
+import (
+	"fmt"
+
+	"github.com/drakmaniso/carol/key"
+	"github.com/drakmaniso/carol/mouse"
+)
+
+//------------------------------------------------------------------------------
+
+// A Binding designates a single physical input: a key (by position), a mouse
+// button, or a direction of the mouse wheel. Only one of the fields is
+// non-zero.
+type Binding struct {
+	Key   key.Position `json:",omitempty"`
+	Mouse mouse.Button `json:",omitempty"`
+	Wheel Wheel        `json:",omitempty"`
+}
+
+// A Wheel direction.
+type Wheel int8
+
+// Wheel directions.
+const (
+	WheelUp    Wheel = 1
+	WheelDown  Wheel = 2
+	WheelLeft  Wheel = 3
+	WheelRight Wheel = 4
+)
+
+//------------------------------------------------------------------------------
+
+// Key returns a binding to the key at a specific position.
+func Key(p key.Position) Binding {
+	return Binding{Key: p}
+}
+
+// Mouse returns a binding to a mouse button.
+func Mouse(b mouse.Button) Binding {
+	return Binding{Mouse: b}
+}
+
+// MouseWheel returns a binding to a direction of the mouse wheel.
+func MouseWheel(w Wheel) Binding {
+	return Binding{Wheel: w}
+}
+
+//------------------------------------------------------------------------------
+
+// IsNone returns true for the empty binding.
+func (b Binding) IsNone() bool {
+	return b == Binding{}
+}
+
+// value returns the current state of the physical input: 0 or 1 for keys and
+// buttons, and the amount of motion since the previous step for the wheel.
+func (b Binding) value() float32 {
+	switch {
+	case b.Key != 0:
+		if key.IsPressed(b.Key) {
+			return 1
+		}
+	case b.Mouse != 0:
+		if mouse.IsPressed(b.Mouse) {
+			return 1
+		}
+	case b.Wheel != 0:
+		dx, dy := mouse.Wheel()
+		var v int32
+		switch b.Wheel {
+		case WheelUp:
+			v = dy
+		case WheelDown:
+			v = -dy
+		case WheelRight:
+			v = dx
+		case WheelLeft:
+			v = -dx
+		}
+		if v > 0 {
+			return float32(v)
+		}
+	}
+	return 0
+}
+
+// String returns a description of the binding, using the label of the key in
+// the current keyboard layout.
+func (b Binding) String() string {
+	switch {
+	case b.Key != 0:
+		l := key.LabelOf(b.Key)
+		if l > ' ' && l < 0x7F {
+			return fmt.Sprintf("key %q", rune(l))
+		}
+		return fmt.Sprintf("key #%d", b.Key)
+	case b.Mouse != 0:
+		return fmt.Sprintf("mouse button %d", b.Mouse)
+	case b.Wheel != 0:
+		switch b.Wheel {
+		case WheelUp:
+			return "wheel up"
+		case WheelDown:
+			return "wheel down"
+		case WheelLeft:
+			return "wheel left"
+		case WheelRight:
+			return "wheel right"
+		}
+	}
+	return "none"
+}
+
+//------------------------------------------------------------------------------
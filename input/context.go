@@ -0,0 +1,248 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package input
+
+//------------------------------------------------------------------------------
+
+import (
+	"fmt"
+)
+
+//------------------------------------------------------------------------------
+
+// A Bool is the name of a digital action (e.g. "jump").
+type Bool string
+
+// An Axis is the name of an analog action (e.g. "move x").
+type Axis string
+
+// An AxisBinding binds an axis to two physical inputs, one for each direction.
+type AxisBinding struct {
+	Negative Binding
+	Positive Binding
+}
+
+//------------------------------------------------------------------------------
+
+// A Context is a set of bindings, used at a specific time in the game (e.g. in
+// menus, or during gameplay).
+type Context struct {
+	name  string
+	bools map[Bool][]Binding
+	axes  map[Axis][]AxisBinding
+}
+
+var contexts = map[string]*Context{}
+
+var active *Context
+
+//------------------------------------------------------------------------------
+
+// NewContext returns a new, empty context. If a context with the same name
+// already exists, it is replaced.
+func NewContext(name string) *Context {
+	c := &Context{
+		name:  name,
+		bools: map[Bool][]Binding{},
+		axes:  map[Axis][]AxisBinding{},
+	}
+	if active != nil && active.name == name {
+		active = c
+	}
+	contexts[name] = c
+	return c
+}
+
+// GetContext returns the context with a specific name, or nil if there is
+// none.
+func GetContext(name string) *Context {
+	return contexts[name]
+}
+
+// Name returns the name of the context.
+func (c *Context) Name() string {
+	return c.name
+}
+
+// Activate makes a context the one used by all actions. A nil context
+// deactivates all actions.
+func Activate(c *Context) {
+	active = c
+}
+
+// Active returns the currently active context.
+func Active() *Context {
+	return active
+}
+
+//------------------------------------------------------------------------------
+
+// A ConflictError is returned when a binding is already used by another
+// action of the same context.
+type ConflictError struct {
+	Context string
+	Binding Binding
+	Action  string
+}
+
+func (e ConflictError) Error() string {
+	return fmt.Sprintf(
+		"%s is already bound to %q in context %q",
+		e.Binding, e.Action, e.Context,
+	)
+}
+
+// Conflict returns the name of the action using b in the context, or an empty
+// string if there is none. The action except, which can be a Bool, an Axis or
+// nil, is ignored: a Bool and an Axis with the same name are distinct actions.
+func (c *Context) Conflict(b Binding, except interface{}) string {
+	if b.IsNone() {
+		return ""
+	}
+	for a, bb := range c.bools {
+		if e, ok := except.(Bool); ok && a == e {
+			continue
+		}
+		for _, x := range bb {
+			if x == b {
+				return string(a)
+			}
+		}
+	}
+	for a, bb := range c.axes {
+		if e, ok := except.(Axis); ok && a == e {
+			continue
+		}
+		for _, x := range bb {
+			if x.Negative == b || x.Positive == b {
+				return string(a)
+			}
+		}
+	}
+	return ""
+}
+
+func (c *Context) check(action interface{}, bindings ...Binding) error {
+	for _, b := range bindings {
+		if a := c.Conflict(b, action); a != "" {
+			return ConflictError{Context: c.name, Binding: b, Action: a}
+		}
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// Bind adds bindings to a digital action. If one of the bindings is already
+// used by another action, nothing is changed and a ConflictError is returned.
+func (c *Context) Bind(a Bool, bindings ...Binding) error {
+	err := c.check(a, bindings...)
+	if err != nil {
+		return err
+	}
+	c.bools[a] = append(c.bools[a], bindings...)
+	return nil
+}
+
+// BindAxis adds a binding to an analog action. If one of the physical inputs
+// is already used by another action, nothing is changed and a ConflictError
+// is returned.
+func (c *Context) BindAxis(a Axis, negative, positive Binding) error {
+	err := c.check(a, negative, positive)
+	if err != nil {
+		return err
+	}
+	c.axes[a] = append(c.axes[a], AxisBinding{Negative: negative, Positive: positive})
+	return nil
+}
+
+// Rebind replaces the i-th binding of a digital action (or adds it, if i is
+// the number of bindings). If the new binding is already used by another
+// action, nothing is changed and a ConflictError is returned.
+func (c *Context) Rebind(a Bool, i int, b Binding) error {
+	bb := c.bools[a]
+	if i < 0 || i > len(bb) {
+		return fmt.Errorf("no binding #%d for action %q", i, a)
+	}
+	err := c.check(a, b)
+	if err != nil {
+		return err
+	}
+	if i == len(bb) {
+		c.bools[a] = append(bb, b)
+	} else {
+		bb[i] = b
+	}
+	return nil
+}
+
+// RebindAxis replaces the i-th binding of an analog action (or adds it, if i
+// is the number of bindings). If one of the physical inputs is already used by
+// another action, nothing is changed and a ConflictError is returned.
+func (c *Context) RebindAxis(a Axis, i int, negative, positive Binding) error {
+	bb := c.axes[a]
+	if i < 0 || i > len(bb) {
+		return fmt.Errorf("no binding #%d for action %q", i, a)
+	}
+	err := c.check(a, negative, positive)
+	if err != nil {
+		return err
+	}
+	ab := AxisBinding{Negative: negative, Positive: positive}
+	if i == len(bb) {
+		c.axes[a] = append(bb, ab)
+	} else {
+		bb[i] = ab
+	}
+	return nil
+}
+
+// Unbind removes all the bindings of an action (either digital or analog).
+func (c *Context) Unbind(action string) {
+	delete(c.bools, Bool(action))
+	delete(c.axes, Axis(action))
+}
+
+// Bindings returns the bindings of a digital action.
+func (c *Context) Bindings(a Bool) []Binding {
+	return append([]Binding(nil), c.bools[a]...)
+}
+
+// AxisBindings returns the bindings of an analog action.
+func (c *Context) AxisBindings(a Axis) []AxisBinding {
+	return append([]AxisBinding(nil), c.axes[a]...)
+}
+
+//------------------------------------------------------------------------------
+
+// IsPressed returns true if one of the bindings of the action, in the active
+// context, is pressed (or if the wheel moved in the bound direction since the
+// previous Update step).
+func (a Bool) IsPressed() bool {
+	if active == nil {
+		return false
+	}
+	for _, b := range active.bools[a] {
+		if b.value() > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Value returns the current value of the action in the active context: -1, 0
+// or 1 for keys and buttons, or the amount of motion for the wheel. If several
+// bindings are active, their values are added.
+func (a Axis) Value() float32 {
+	if active == nil {
+		return 0
+	}
+	var v float32
+	for _, b := range active.axes[a] {
+		v += b.Positive.value() - b.Negative.value()
+	}
+	return v
+}
+
+//------------------------------------------------------------------------------
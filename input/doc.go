@@ -0,0 +1,30 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+/*
+Package input maps named actions to keys, mouse buttons and wheel.
+
+Instead of testing key positions directly, the game declares its actions:
+
+  const (
+    Jump  input.Bool = "jump"
+    MoveX input.Axis = "move x"
+  )
+
+binds them in one or more contexts:
+
+  game := input.NewContext("game")
+  game.Bind(Jump, input.Key(key.PositionSpace))
+  game.BindAxis(MoveX, input.Key(key.PositionA), input.Key(key.PositionD))
+  input.Activate(game)
+
+and queries them during Update:
+
+  if Jump.IsPressed() {
+    ...
+  }
+
+Bindings can be changed at runtime (e.g. from an options menu), and saved or
+loaded as JSON.
+*/
+package input
@@ -0,0 +1,86 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package input
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/drakmaniso/carol/key"
+	"github.com/drakmaniso/carol/mouse"
+)
+
+//------------------------------------------------------------------------------
+
+const (
+	jump  Bool = "jump"
+	fire  Bool = "fire"
+	moveX Axis = "move x"
+)
+
+func TestConflicts(t *testing.T) {
+	c := NewContext("test")
+	if err := c.Bind(jump, Key(key.PositionSpace)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.BindAxis(moveX, Key(key.PositionA), Key(key.PositionD)); err != nil {
+		t.Fatal(err)
+	}
+
+	err := c.Bind(fire, Mouse(mouse.Left), Key(key.PositionD))
+	if e, ok := err.(ConflictError); !ok || e.Action != string(moveX) {
+		t.Errorf("expected conflict with %q, got %v", moveX, err)
+	}
+	if len(c.Bindings(fire)) != 0 {
+		t.Errorf("conflicting bind modified the context")
+	}
+
+	if err := c.Rebind(jump, 0, Key(key.PositionW)); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if err := c.Rebind(jump, 1, Key(key.PositionW)); err != nil {
+		t.Errorf("rebinding to the same action should not conflict: %s", err)
+	}
+	if err := c.RebindAxis(moveX, 0, Key(key.PositionW), Key(key.PositionD)); err == nil {
+		t.Errorf("expected conflict with %q", jump)
+	}
+
+	// A digital and an analog action with the same name are distinct
+	err = c.BindAxis(Axis(jump), Key(key.PositionW), Key(key.PositionS))
+	if e, ok := err.(ConflictError); !ok || e.Action != string(jump) {
+		t.Errorf("expected conflict with %q, got %v", jump, err)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	c := NewContext("saved")
+	if err := c.Bind(jump, Key(key.PositionSpace), MouseWheel(WheelUp)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.BindAxis(moveX, Key(key.PositionLeft), Key(key.PositionRight)); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	NewContext("saved")
+	if err := Load(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	c = GetContext("saved")
+	b := c.Bindings(jump)
+	if len(b) != 2 || b[0] != Key(key.PositionSpace) || b[1] != MouseWheel(WheelUp) {
+		t.Errorf("unexpected bindings after load: %v", b)
+	}
+	ab := c.AxisBindings(moveX)
+	if len(ab) != 1 || ab[0].Positive != Key(key.PositionRight) {
+		t.Errorf("unexpected axis bindings after load: %v", ab)
+	}
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,78 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package input
+
+//------------------------------------------------------------------------------
+
+import (
+	"encoding/json"
+	"io"
+)
+
+//------------------------------------------------------------------------------
+
+type savedContext struct {
+	Bools map[Bool][]Binding     `json:",omitempty"`
+	Axes  map[Axis][]AxisBinding `json:",omitempty"`
+}
+
+//------------------------------------------------------------------------------
+
+// Save writes the bindings of all contexts, in JSON format.
+func Save(w io.Writer) error {
+	s := make(map[string]savedContext, len(contexts))
+	for n, c := range contexts {
+		s[n] = savedContext{Bools: c.bools, Axes: c.axes}
+	}
+	e := json.NewEncoder(w)
+	e.SetIndent("", "\t")
+	return e.Encode(s)
+}
+
+// Load reads bindings saved with Save. Each context found in r replaces the
+// existing one with the same name; the other contexts are kept.
+//
+// The bindings are checked for conflicts, and nothing is changed if one is
+// found.
+func Load(r io.Reader) error {
+	var s map[string]savedContext
+	err := json.NewDecoder(r).Decode(&s)
+	if err != nil {
+		return err
+	}
+
+	loaded := make(map[string]*Context, len(s))
+	for n, sc := range s {
+		c := &Context{
+			name:  n,
+			bools: map[Bool][]Binding{},
+			axes:  map[Axis][]AxisBinding{},
+		}
+		for a, bb := range sc.Bools {
+			err := c.Bind(a, bb...)
+			if err != nil {
+				return err
+			}
+		}
+		for a, bb := range sc.Axes {
+			for _, b := range bb {
+				err := c.BindAxis(a, b.Negative, b.Positive)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		loaded[n] = c
+	}
+
+	for n, c := range loaded {
+		if active != nil && active.name == n {
+			active = c
+		}
+		contexts[n] = c
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
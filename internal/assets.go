@@ -0,0 +1,128 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package internal
+
+//------------------------------------------------------------------------------
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+//------------------------------------------------------------------------------
+
+// Assets is the file system used to load all game assets.
+var Assets = &LayeredFS{}
+
+// A LayeredFS is a file system made of several layers: each file is looked up
+// in the most recently mounted layer first. Directory listings are merged.
+//
+// Without any layer, it uses the directory of the executable.
+type LayeredFS struct {
+	layers []fs.FS
+}
+
+// Mount adds a layer on top of the others.
+func (l *LayeredFS) Mount(fsys fs.FS) {
+	l.layers = append(l.layers, fsys)
+}
+
+// UnmountAll removes all layers.
+func (l *LayeredFS) UnmountAll() {
+	l.layers = nil
+}
+
+// top returns the layers, from the most recently mounted.
+func (l *LayeredFS) top() []fs.FS {
+	if len(l.layers) == 0 {
+		d := FilePath
+		if d == "" {
+			d = "."
+		}
+		return []fs.FS{os.DirFS(d)}
+	}
+	t := make([]fs.FS, len(l.layers))
+	for i := range l.layers {
+		t[i] = l.layers[len(l.layers)-1-i]
+	}
+	return t
+}
+
+//------------------------------------------------------------------------------
+
+// Open implements fs.FS.
+func (l *LayeredFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	for _, t := range l.top() {
+		f, err := t.Open(name)
+		if !errors.Is(err, fs.ErrNotExist) {
+			return f, err
+		}
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// Stat implements fs.StatFS.
+func (l *LayeredFS) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+	for _, t := range l.top() {
+		i, err := fs.Stat(t, name)
+		if !errors.Is(err, fs.ErrNotExist) {
+			return i, err
+		}
+	}
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadDir implements fs.ReadDirFS. The entries of all layers are merged; when
+// several layers have the same entry, the top-most one is used.
+func (l *LayeredFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	found := false
+	seen := map[string]bool{}
+	var d []fs.DirEntry
+	for _, t := range l.top() {
+		e, err := fs.ReadDir(t, name)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			continue
+		case err != nil:
+			return nil, err
+		}
+		found = true
+		for _, e := range e {
+			if !seen[e.Name()] {
+				seen[e.Name()] = true
+				d = append(d, e)
+			}
+		}
+	}
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	sort.Slice(d, func(i, j int) bool { return d[i].Name() < d[j].Name() })
+	return d, nil
+}
+
+//------------------------------------------------------------------------------
+
+// OpenAsset opens a file from the asset file system, unless the name is an
+// absolute path, in which case it is opened directly.
+func OpenAsset(name string) (fs.File, error) {
+	if filepath.IsAbs(name) {
+		return os.Open(name)
+	}
+	return Assets.Open(name)
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,29 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+#include <string.h>
+#include "sdl.h"
+#include "_cgo_export.h"
+
+static void audioCallback(void *userdata, Uint8 *stream, int len)
+{
+  audioMix(stream, len);
+}
+
+SDL_AudioDeviceID OpenAudio(int freq, int samples, int *obtained)
+{
+  SDL_AudioSpec want, have;
+  memset(&want, 0, sizeof(want));
+  want.freq = freq;
+  want.format = AUDIO_F32SYS;
+  want.channels = 2;
+  want.samples = samples;
+  want.callback = audioCallback;
+
+  SDL_AudioDeviceID d = SDL_OpenAudioDevice(
+      NULL, 0, &want, &have, SDL_AUDIO_ALLOW_FREQUENCY_CHANGE);
+  if (d != 0) {
+    *obtained = have.freq;
+  }
+  return d;
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package internal
+
+//------------------------------------------------------------------------------
+
+import (
+	"errors"
+	"unsafe"
+)
+
+//------------------------------------------------------------------------------
+
+/*
+#include "sdl.h"
+
+SDL_AudioDeviceID OpenAudio(int freq, int samples, int *obtained);
+*/
+import "C"
+
+//------------------------------------------------------------------------------
+
+var audioDevice C.SDL_AudioDeviceID
+
+// audioMixer fills the buffers requested by the audio device.
+var audioMixer func(out []float32)
+
+// AudioOpen opens the default audio device, for stereo float samples. The
+// mixer is called from the audio thread with interleaved samples to fill. It
+// returns the actual sample rate, which may differ from the requested one.
+func AudioOpen(rate, frames int, mixer func(out []float32)) (int, error) {
+	if audioDevice != 0 {
+		return 0, errors.New("audio device already open")
+	}
+	if errcode := C.SDL_InitSubSystem(C.SDL_INIT_AUDIO); errcode != 0 {
+		return 0, Error("in SDL audio initialization", GetSDLError())
+	}
+	audioMixer = mixer
+	var r C.int
+	audioDevice = C.OpenAudio(C.int(rate), C.int(frames), &r)
+	if audioDevice == 0 {
+		C.SDL_QuitSubSystem(C.SDL_INIT_AUDIO)
+		return 0, Error("while opening audio device", GetSDLError())
+	}
+	C.SDL_PauseAudioDevice(audioDevice, 0)
+	return int(r), nil
+}
+
+// AudioClose closes the audio device, if open.
+func AudioClose() {
+	if audioDevice == 0 {
+		return
+	}
+	C.SDL_CloseAudioDevice(audioDevice)
+	C.SDL_QuitSubSystem(C.SDL_INIT_AUDIO)
+	audioDevice = 0
+	audioMixer = nil
+}
+
+//export audioMix
+func audioMix(stream unsafe.Pointer, length C.int) {
+	out := (*[1 << 28]float32)(stream)[: length/4 : length/4]
+	if audioMixer == nil {
+		for i := range out {
+			out[i] = 0
+		}
+		return
+	}
+	audioMixer(out)
+}
+
+//------------------------------------------------------------------------------
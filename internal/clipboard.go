@@ -0,0 +1,48 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package internal
+
+//------------------------------------------------------------------------------
+
+import (
+	"unsafe"
+)
+
+//------------------------------------------------------------------------------
+
+/*
+#include <stdlib.h>
+#include "sdl.h"
+*/
+import "C"
+
+//------------------------------------------------------------------------------
+
+// ClipboardText returns the text content of the clipboard.
+func ClipboardText() (string, error) {
+	if C.SDL_HasClipboardText() != C.SDL_TRUE {
+		return "", nil
+	}
+	t := C.SDL_GetClipboardText()
+	if t == nil {
+		return "", Error("in clipboard access", GetSDLError())
+	}
+	defer C.SDL_free(unsafe.Pointer(t))
+	if *t == 0 {
+		return "", Error("in clipboard access", GetSDLError())
+	}
+	return C.GoString(t), nil
+}
+
+// ClipboardSetText replaces the content of the clipboard.
+func ClipboardSetText(text string) error {
+	t := C.CString(text)
+	defer C.free(unsafe.Pointer(t))
+	if C.SDL_SetClipboardText(t) != 0 {
+		return Error("in clipboard access", GetSDLError())
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
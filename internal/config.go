@@ -0,0 +1,254 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package internal
+
+//------------------------------------------------------------------------------
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+
+// ConfigFile is the name of the (optional) configuration file, in the asset
+// file system. It's ignored if empty.
+var ConfigFile = "init.json"
+
+// SettingsFile is the name of the per-user settings file, where runtime
+// changes to the configuration are saved.
+const SettingsFile = "settings.json"
+
+// configEnvPrefix is the prefix of environment variables overriding the
+// configuration (e.g. CAROL_FULLSCREEN=true).
+const configEnvPrefix = "CAROL_"
+
+// configArgPrefix is the prefix of command-line arguments overriding the
+// configuration, after the leading dash(es) (e.g. -carol.Fullscreen=true).
+const configArgPrefix = "carol."
+
+//------------------------------------------------------------------------------
+
+// LoadConfig completes the configuration by applying, in order: the
+// configuration file, the per-user settings file, the environment variables
+// and the command-line arguments. The result is then validated.
+//
+// The command-line arguments used by the configuration are removed from
+// os.Args, so that they don't interfere with the flag package.
+func LoadConfig() error {
+	if ConfigFile != "" {
+		err := loadConfigFile(Assets, ConfigFile)
+		if err != nil {
+			return Error(`in configuration file "`+ConfigFile+`"`, err)
+		}
+	}
+
+	if p := SettingsPath(); p != "" {
+		err := loadConfigFile(os.DirFS(filepath.Dir(p)), filepath.Base(p))
+		if err != nil {
+			return Error(`in settings file "`+p+`"`, err)
+		}
+	}
+
+	for _, n := range configFieldNames() {
+		v, ok := os.LookupEnv(configEnvPrefix + strings.ToUpper(n))
+		if !ok {
+			continue
+		}
+		err := setConfigField(n, v)
+		if err != nil {
+			return Error("in environment variable "+configEnvPrefix+strings.ToUpper(n), err)
+		}
+	}
+
+	if len(os.Args) > 0 {
+		args := os.Args[:1]
+		for _, a := range os.Args[1:] {
+			// Accept both "-" and "--"
+			a2 := strings.TrimPrefix(strings.TrimPrefix(a, "-"), "-")
+			if !strings.HasPrefix(a2, configArgPrefix) {
+				args = append(args, a)
+				continue
+			}
+			a2 = strings.TrimPrefix(a2, configArgPrefix)
+			n, v := a2, "true"
+			if i := strings.IndexByte(a2, '='); i >= 0 {
+				n, v = a2[:i], a2[i+1:]
+			}
+			err := setConfigField(n, v)
+			if err != nil {
+				return Error(`in command-line argument "`+a+`"`, err)
+			}
+		}
+		os.Args = args
+	}
+
+	return Error("in configuration", Config.Validate())
+}
+
+func loadConfigFile(fsys fs.FS, name string) error {
+	f, err := fsys.Open(name)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil
+	case err != nil:
+		return err
+	}
+	defer f.Close()
+
+	d := json.NewDecoder(f)
+	return d.Decode(&Config)
+}
+
+//------------------------------------------------------------------------------
+
+func configFieldNames() []string {
+	t := reflect.TypeOf(Config)
+	n := make([]string, t.NumField())
+	for i := range n {
+		n[i] = t.Field(i).Name
+	}
+	return n
+}
+
+// setConfigField sets a field of the configuration (designated by its
+// case-insensitive name) from a string. String fields are used verbatim, other
+// fields are parsed as JSON (the brackets around arrays are optional).
+func setConfigField(name, value string) error {
+	f := reflect.ValueOf(&Config).Elem().FieldByNameFunc(func(n string) bool {
+		return strings.EqualFold(n, name)
+	})
+	if !f.IsValid() {
+		return fmt.Errorf("unknown configuration option %q", name)
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(value)
+		return nil
+	case reflect.Array:
+		if !strings.HasPrefix(value, "[") {
+			value = "[" + value + "]"
+		}
+	}
+
+	err := json.Unmarshal([]byte(value), f.Addr().Interface())
+	if err != nil {
+		return fmt.Errorf("invalid value %q for option %q", value, name)
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// Validate returns an error describing all the problems of the configuration,
+// or nil if there is none.
+func (c *Configuration) Validate() error {
+	var p []string
+
+	if c.WindowSize[0] < 1 || c.WindowSize[1] < 1 {
+		p = append(p, fmt.Sprintf("invalid WindowSize %dx%d", c.WindowSize[0], c.WindowSize[1]))
+	}
+	if c.ScreenSize[0] < 1 || c.ScreenSize[1] < 1 {
+		p = append(p, fmt.Sprintf("invalid ScreenSize %dx%d", c.ScreenSize[0], c.ScreenSize[1]))
+	}
+
+	switch c.ScreenMode {
+	case "Fit", "Zoom", "direct":
+	case "Extend":
+		if c.PixelSize > c.WindowSize[0] || c.PixelSize > c.WindowSize[1] {
+			p = append(p, fmt.Sprintf(
+				"PixelSize %d is too big for a %dx%d window",
+				c.PixelSize, c.WindowSize[0], c.WindowSize[1],
+			))
+		}
+	case "Fixed":
+		w := int32(c.ScreenSize[0]) * c.PixelSize
+		h := int32(c.ScreenSize[1]) * c.PixelSize
+		if w > c.WindowSize[0] || h > c.WindowSize[1] {
+			p = append(p, fmt.Sprintf(
+				"PixelSize %d is too big: a %dx%d screen does not fit in a %dx%d window",
+				c.PixelSize, c.ScreenSize[0], c.ScreenSize[1], c.WindowSize[0], c.WindowSize[1],
+			))
+		}
+	default:
+		p = append(p, fmt.Sprintf(
+			`unknown ScreenMode %q (must be "Fit", "Extend", "Zoom" or "Fixed")`,
+			c.ScreenMode,
+		))
+	}
+
+	if c.PixelSize < 1 {
+		p = append(p, fmt.Sprintf("invalid PixelSize %d (must be at least 1)", c.PixelSize))
+	}
+
+	switch c.FullscreenMode {
+	case "Desktop", "Exclusive":
+	default:
+		p = append(p, fmt.Sprintf(
+			`unknown FullscreenMode %q (must be "Desktop" or "Exclusive")`,
+			c.FullscreenMode,
+		))
+	}
+
+	if c.Multisample < 0 {
+		p = append(p, fmt.Sprintf("invalid Multisample %d", c.Multisample))
+	}
+	if c.Display < 0 {
+		p = append(p, fmt.Sprintf("invalid Display %d", c.Display))
+	}
+
+	if len(p) > 0 {
+		return errors.New(strings.Join(p, "; "))
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// SettingsPath returns the path of the per-user settings file, or an empty
+// string if there is no suitable directory.
+func SettingsPath() string {
+	d, err := os.UserConfigDir()
+	if err != nil || Config.Title == "" {
+		return ""
+	}
+	return filepath.Join(d, Config.Title, SettingsFile)
+}
+
+// SaveSettings writes the parts of the configuration that can be changed at
+// runtime to the per-user settings file.
+func SaveSettings() error {
+	p := SettingsPath()
+	if p == "" {
+		return nil
+	}
+
+	s := struct {
+		Fullscreen     bool
+		FullscreenMode string
+	}{
+		Fullscreen:     Config.Fullscreen,
+		FullscreenMode: Config.FullscreenMode,
+	}
+	b, err := json.MarshalIndent(s, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(p), 0755)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(p, b, 0644)
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,82 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package internal
+
+//------------------------------------------------------------------------------
+
+import (
+	"image"
+)
+
+//------------------------------------------------------------------------------
+
+/*
+#include <stdlib.h>
+#include "sdl.h"
+*/
+import "C"
+
+//------------------------------------------------------------------------------
+
+// CursorVisible is true if the system cursor should be shown (outside of the
+// relative mouse mode).
+var CursorVisible = true
+
+var cursor *C.SDL_Cursor
+
+//------------------------------------------------------------------------------
+
+// CursorShow shows or hides the system cursor.
+func CursorShow(visible bool) {
+	CursorVisible = visible
+	cursorApply()
+}
+
+func cursorApply() {
+	if CursorVisible {
+		C.SDL_ShowCursor(C.SDL_ENABLE)
+	} else {
+		C.SDL_ShowCursor(C.SDL_DISABLE)
+	}
+}
+
+// CursorSetImage replaces the system cursor with an image.
+func CursorSetImage(img *image.NRGBA, hotX, hotY int32) error {
+	b := img.Bounds()
+
+	// The surface must not point to Go memory
+	p := C.CBytes(img.Pix)
+	defer C.free(p)
+	s := C.SDL_CreateRGBSurfaceWithFormatFrom(
+		p,
+		C.int(b.Dx()), C.int(b.Dy()), 32, C.int(img.Stride),
+		C.SDL_PIXELFORMAT_RGBA32,
+	)
+	if s == nil {
+		return Error("in cursor creation", GetSDLError())
+	}
+	defer C.SDL_FreeSurface(s)
+
+	c := C.SDL_CreateColorCursor(s, C.int(hotX), C.int(hotY))
+	if c == nil {
+		return Error("in cursor creation", GetSDLError())
+	}
+	C.SDL_SetCursor(c)
+	if cursor != nil {
+		C.SDL_FreeCursor(cursor)
+	}
+	cursor = c
+	return nil
+}
+
+// CursorReset restores the default system cursor.
+func CursorReset() {
+	C.SDL_SetCursor(C.SDL_GetDefaultCursor())
+	if cursor != nil {
+		C.SDL_FreeCursor(cursor)
+		cursor = nil
+	}
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,344 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package internal
+
+//------------------------------------------------------------------------------
+
+import (
+	"errors"
+)
+
+//------------------------------------------------------------------------------
+
+// An EventKind identifies the type of an Event.
+type EventKind uint8
+
+// The kinds of events handled by the game loop. All the kinds after
+// EventKeyDown are user input.
+const (
+	EventNone EventKind = iota
+	EventQuit
+	EventWindowShown
+	EventWindowHidden
+	EventWindowResized
+	EventWindowMinimized
+	EventWindowMaximized
+	EventWindowRestored
+	EventWindowMouseEnter
+	EventWindowMouseLeave
+	EventWindowFocusGained
+	EventWindowFocusLost
+	EventKeyDown
+	EventKeyUp
+	EventMouseMotion
+	EventMouseButtonDown
+	EventMouseButtonUp
+	EventMouseWheel
+	EventGamepadConnected
+	EventGamepadDisconnected
+	EventGamepadButtonDown
+	EventGamepadButtonUp
+	EventGamepadAxisMotion
+	EventTextInput
+	EventTextEditing
+	EventFileDropped
+	EventTextDropped
+	EventTouchDown
+	EventTouchMove
+	EventTouchUp
+)
+
+var eventNames = [...]string{
+	EventNone:              "None",
+	EventQuit:              "Quit",
+	EventWindowShown:       "WindowShown",
+	EventWindowHidden:      "WindowHidden",
+	EventWindowResized:     "WindowResized",
+	EventWindowMinimized:   "WindowMinimized",
+	EventWindowMaximized:   "WindowMaximized",
+	EventWindowRestored:    "WindowRestored",
+	EventWindowMouseEnter:  "WindowMouseEnter",
+	EventWindowMouseLeave:  "WindowMouseLeave",
+	EventWindowFocusGained: "WindowFocusGained",
+	EventWindowFocusLost:   "WindowFocusLost",
+	EventKeyDown:           "KeyDown",
+	EventKeyUp:             "KeyUp",
+	EventMouseMotion:       "MouseMotion",
+	EventMouseButtonDown:   "MouseButtonDown",
+	EventMouseButtonUp:     "MouseButtonUp",
+	EventMouseWheel:        "MouseWheel",
+
+	EventGamepadConnected:    "GamepadConnected",
+	EventGamepadDisconnected: "GamepadDisconnected",
+	EventGamepadButtonDown:   "GamepadButtonDown",
+	EventGamepadButtonUp:     "GamepadButtonUp",
+	EventGamepadAxisMotion:   "GamepadAxisMotion",
+
+	EventTextInput:   "TextInput",
+	EventTextEditing: "TextEditing",
+
+	EventFileDropped: "FileDropped",
+	EventTextDropped: "TextDropped",
+
+	EventTouchDown: "TouchDown",
+	EventTouchMove: "TouchMove",
+	EventTouchUp:   "TouchUp",
+}
+
+// String returns the name of the event kind.
+func (k EventKind) String() string {
+	if int(k) < len(eventNames) {
+		return eventNames[k]
+	}
+	return "Unknown"
+}
+
+// IsInput returns true for the events caused by user input, as opposed to
+// window events.
+func (k EventKind) IsInput() bool {
+	return k >= EventKeyDown
+}
+
+// MarshalText implements encoding.TextMarshaler, so that event kinds are
+// recorded by name.
+func (k EventKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (k *EventKind) UnmarshalText(text []byte) error {
+	for i, n := range eventNames {
+		if n == string(text) {
+			*k = EventKind(i)
+			return nil
+		}
+	}
+	return errors.New(`unknown event kind "` + string(text) + `"`)
+}
+
+//------------------------------------------------------------------------------
+
+// An Event is a copy of an SDL event, independent from SDL. Only the fields
+// relevant to the kind of event are used:
+//
+// - KeyDown and KeyUp use Label and Position;
+//
+// - MouseMotion uses DX and DY for the relative motion, X and Y for the
+// position, and Buttons for the state of all buttons;
+//
+// - MouseButtonDown and MouseButtonUp use Button and Clicks;
+//
+// - MouseWheel uses DX and DY;
+//
+// - WindowResized uses X and Y for the new size;
+//
+// - all gamepad events use Gamepad for the slot of the device; in addition,
+// GamepadButtonDown and GamepadButtonUp use PadButton, and GamepadAxisMotion
+// uses PadAxis and Value;
+//
+// - TextInput uses Text, and TextEditing uses Text, Start and Length;
+//
+// - FileDropped uses Text for the path of the file, and TextDropped for the
+// text;
+//
+// - all touch events use Finger, TouchX and TouchY for the normalized position,
+// and Pressure; TouchMove also uses TouchDX and TouchDY.
+type Event struct {
+	Kind     EventKind
+	Time     float64
+	Label    KeyLabel    `json:",omitempty"`
+	Position KeyPosition `json:",omitempty"`
+	Button   MouseButton `json:",omitempty"`
+	Clicks   int32       `json:",omitempty"`
+	Buttons  uint32      `json:",omitempty"`
+	X        int32       `json:",omitempty"`
+	Y        int32       `json:",omitempty"`
+	DX       int32       `json:",omitempty"`
+	DY       int32       `json:",omitempty"`
+
+	Gamepad   int32         `json:",omitempty"`
+	PadButton GamepadButton `json:",omitempty"`
+	PadAxis   GamepadAxis   `json:",omitempty"`
+	Value     int16         `json:",omitempty"`
+
+	Text   string `json:",omitempty"`
+	Start  int32  `json:",omitempty"`
+	Length int32  `json:",omitempty"`
+
+	Finger   int64   `json:",omitempty"`
+	TouchX   float32 `json:",omitempty"`
+	TouchY   float32 `json:",omitempty"`
+	TouchDX  float32 `json:",omitempty"`
+	TouchDY  float32 `json:",omitempty"`
+	Pressure float32 `json:",omitempty"`
+}
+
+//------------------------------------------------------------------------------
+
+// RecentEventsMax is the number of events kept by RecentEvents.
+const RecentEventsMax = 64
+
+var recentEvents struct {
+	events      [RecentEventsMax]Event
+	next, count int
+}
+
+// RecentEvents returns the last events dispatched, from the oldest.
+func RecentEvents() []Event {
+	r := make([]Event, recentEvents.count)
+	for i := range r {
+		r[i] = recentEvents.events[(recentEvents.next-recentEvents.count+i+RecentEventsMax)%RecentEventsMax]
+	}
+	return r
+}
+
+//------------------------------------------------------------------------------
+
+// FilterEvent, if not nil, is called for each event coming from SDL; the event
+// is dropped if it returns false.
+var FilterEvent func(e Event) bool
+
+// RecordEvent, if not nil, is called for each event dispatched.
+var RecordEvent func(e Event)
+
+// Dispatch updates the input state according to an event, and calls the
+// corresponding method of the game loop.
+func Dispatch(e Event) {
+	if !e.valid() {
+		Debug.Printf("dropped invalid %s event", e.Kind)
+		return
+	}
+	if RecordEvent != nil {
+		RecordEvent(e)
+	}
+	recentEvents.events[recentEvents.next] = e
+	recentEvents.next = (recentEvents.next + 1) % RecentEventsMax
+	if recentEvents.count < RecentEventsMax {
+		recentEvents.count++
+	}
+	handle(e)
+}
+
+// valid returns false if the event refers to a device, button or axis out of
+// range (which may happen with scripted or replayed events).
+func (e Event) valid() bool {
+	switch e.Kind {
+	case EventKeyDown, EventKeyUp:
+		return int(e.Position) < len(KeyState)
+	case EventMouseButtonDown, EventMouseButtonUp:
+		return e.Button >= 1 && int(e.Button) <= len(MousePressTime)
+	case EventGamepadConnected, EventGamepadDisconnected:
+		return e.Gamepad >= 0 && e.Gamepad < GamepadMax
+	case EventGamepadButtonDown, EventGamepadButtonUp:
+		return e.Gamepad >= 0 && e.Gamepad < GamepadMax &&
+			int(e.PadButton) < GamepadButtonsMax
+	case EventGamepadAxisMotion:
+		return e.Gamepad >= 0 && e.Gamepad < GamepadMax &&
+			int(e.PadAxis) < GamepadAxesMax
+	}
+	return true
+}
+
+// handle does the work of Dispatch. It's also used for the events synthesized
+// from other events, which are neither recorded nor filtered.
+func handle(e Event) {
+	VisibleNow = e.Time
+	switch e.Kind {
+	case EventQuit:
+		Loop.WindowQuit()
+	// Window Events
+	case EventWindowShown:
+		Loop.WindowShown()
+	case EventWindowHidden:
+		Loop.WindowHidden()
+	case EventWindowResized:
+		Window.Width, Window.Height = e.X, e.Y
+		ResizeScreen()
+		Loop.WindowResized(Window.Width, Window.Height)
+	case EventWindowMinimized:
+		Loop.WindowMinimized()
+	case EventWindowMaximized:
+		Loop.WindowMaximized()
+	case EventWindowRestored:
+		Loop.WindowRestored()
+	case EventWindowMouseEnter:
+		HasMouseFocus = true
+		Loop.WindowMouseEnter()
+	case EventWindowMouseLeave:
+		HasMouseFocus = false
+		Loop.WindowMouseLeave()
+	case EventWindowFocusGained:
+		HasFocus = true
+		Loop.WindowFocusGained()
+	case EventWindowFocusLost:
+		HasFocus = false
+		Loop.WindowFocusLost()
+	// Keyboard Events
+	case EventKeyDown:
+		KeyState[e.Position] = true
+		pending.keyPressed[e.Position] = true
+		Loop.KeyDown(e.Label, e.Position)
+	case EventKeyUp:
+		KeyState[e.Position] = false
+		pending.keyReleased[e.Position] = true
+		Loop.KeyUp(e.Label, e.Position)
+	// Mouse Events
+	case EventMouseMotion:
+		MouseDeltaX += e.DX
+		MouseDeltaY += e.DY
+		MousePositionX, MousePositionY = e.X, e.Y
+		MouseButtons = e.Buttons
+		Loop.MouseMotion(e.DX, e.DY, e.X, e.Y)
+	case EventMouseButtonDown:
+		MouseButtons |= 1 << (e.Button - 1)
+		pending.mousePressed |= 1 << (e.Button - 1)
+		Loop.MouseButtonDown(e.Button, int(e.Clicks))
+	case EventMouseButtonUp:
+		MouseButtons &= ^(1 << (e.Button - 1))
+		pending.mouseReleased |= 1 << (e.Button - 1)
+		Loop.MouseButtonUp(e.Button, int(e.Clicks))
+	case EventMouseWheel:
+		pending.wheelX += e.DX
+		pending.wheelY += e.DY
+		Loop.MouseWheel(e.DX, e.DY)
+	// Gamepad Events
+	case EventGamepadConnected:
+		Gamepads[e.Gamepad] = GamepadState{
+			Connected: true,
+			Name:      Gamepads[e.Gamepad].Name,
+		}
+		Loop.GamepadConnected(int(e.Gamepad))
+	case EventGamepadDisconnected:
+		Gamepads[e.Gamepad] = GamepadState{}
+		Loop.GamepadDisconnected(int(e.Gamepad))
+	case EventGamepadButtonDown:
+		Gamepads[e.Gamepad].Buttons[e.PadButton] = true
+		Loop.GamepadButtonDown(int(e.Gamepad), e.PadButton)
+	case EventGamepadButtonUp:
+		Gamepads[e.Gamepad].Buttons[e.PadButton] = false
+		Loop.GamepadButtonUp(int(e.Gamepad), e.PadButton)
+	case EventGamepadAxisMotion:
+		Gamepads[e.Gamepad].Axes[e.PadAxis] = e.Value
+		Loop.GamepadAxisMotion(int(e.Gamepad), e.PadAxis, float32(e.Value)/32767)
+	// Text Input Events
+	case EventTextInput:
+		Loop.TextInput(e.Text)
+	case EventTextEditing:
+		Loop.TextEditing(e.Text, int(e.Start), int(e.Length))
+	// Drag and Drop Events
+	case EventFileDropped:
+		Loop.FileDropped(e.Text)
+	case EventTextDropped:
+		Loop.TextDropped(e.Text)
+	// Touch Events
+	case EventTouchDown:
+		touchDown(e)
+	case EventTouchMove:
+		touchMove(e)
+	case EventTouchUp:
+		touchUp(e)
+	}
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,217 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package internal
+
+//------------------------------------------------------------------------------
+
+import (
+	"errors"
+	"image"
+	"image/draw"
+	"unsafe"
+)
+
+//------------------------------------------------------------------------------
+
+/*
+#include <stdlib.h>
+#include "sdl.h"
+*/
+import "C"
+
+//------------------------------------------------------------------------------
+
+// WindowSetTitle changes the title of the window.
+func WindowSetTitle(title string) {
+	t := C.CString(title)
+	defer C.free(unsafe.Pointer(t))
+	C.SDL_SetWindowTitle(Window.window, t)
+}
+
+// WindowSetSize changes the size of the window (when not fullscreen).
+func WindowSetSize(width, height int32) {
+	C.SDL_SetWindowSize(Window.window, C.int(width), C.int(height))
+}
+
+// WindowSetMinimumSize changes the minimum size of the window.
+func WindowSetMinimumSize(width, height int32) {
+	C.SDL_SetWindowMinimumSize(Window.window, C.int(width), C.int(height))
+}
+
+// WindowPosition returns the position of the window on the desktop.
+func WindowPosition() (x, y int32) {
+	var cx, cy C.int
+	C.SDL_GetWindowPosition(Window.window, &cx, &cy)
+	return int32(cx), int32(cy)
+}
+
+// WindowSetPosition moves the window on the desktop.
+func WindowSetPosition(x, y int32) {
+	C.SDL_SetWindowPosition(Window.window, C.int(x), C.int(y))
+}
+
+// WindowSetBorderless removes or restores the window decorations.
+func WindowSetBorderless(b bool) {
+	var bd C.SDL_bool = C.SDL_TRUE
+	if b {
+		bd = C.SDL_FALSE
+	}
+	C.SDL_SetWindowBordered(Window.window, bd)
+}
+
+// WindowSetAlwaysOnTop keeps the window above the others.
+func WindowSetAlwaysOnTop(t bool) {
+	var on C.SDL_bool = C.SDL_FALSE
+	if t {
+		on = C.SDL_TRUE
+	}
+	C.SDL_SetWindowAlwaysOnTop(Window.window, on)
+}
+
+// WindowSetIcon changes the icon of the window.
+func WindowSetIcon(img image.Image) error {
+	b := img.Bounds()
+	if b.Empty() {
+		return errors.New("empty window icon")
+	}
+	m := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(m, m.Bounds(), img, b.Min, draw.Src)
+
+	// The surface must not point to Go memory
+	p := C.CBytes(m.Pix)
+	defer C.free(p)
+	s := C.SDL_CreateRGBSurfaceWithFormatFrom(
+		p,
+		C.int(b.Dx()), C.int(b.Dy()), 32, C.int(m.Stride),
+		C.SDL_PIXELFORMAT_RGBA32,
+	)
+	if s == nil {
+		return Error("in window icon creation", GetSDLError())
+	}
+	C.SDL_SetWindowIcon(Window.window, s)
+	C.SDL_FreeSurface(s)
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// SetFullscreenMode changes the kind of fullscreen ("Desktop" or "Exclusive"),
+// applies it if the window is fullscreen, and saves it in the per-user
+// settings file.
+func SetFullscreenMode(mode string) error {
+	switch mode {
+	case "Desktop", "Exclusive":
+	default:
+		return errors.New(`unknown fullscreen mode "` + mode + `"`)
+	}
+	Config.FullscreenMode = mode
+	if GetFullscreen() {
+		C.SDL_SetWindowFullscreen(Window.window, 0)
+		SetFullscreen(true)
+		return nil
+	}
+	err := SaveSettings()
+	if err != nil {
+		Debug.Printf("unable to save settings: %s", err)
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// A DisplayMode is a resolution and refresh rate supported by a display.
+type DisplayMode struct {
+	Width, Height int32
+	RefreshRate   int32 // In Hz, or 0 if unknown
+}
+
+// A Display describes a monitor.
+type Display struct {
+	Index               int
+	Name                string
+	X, Y, Width, Height int32 // Bounds on the desktop
+	DPI                 float32
+	HorizontalDPI       float32
+	VerticalDPI         float32
+	Desktop             DisplayMode   // Current mode of the desktop
+	Modes               []DisplayMode // Available fullscreen modes
+}
+
+// Displays returns the list of connected displays.
+func Displays() ([]Display, error) {
+	n := C.SDL_GetNumVideoDisplays()
+	if n < 0 {
+		return nil, Error("in display enumeration", GetSDLError())
+	}
+	dd := make([]Display, n)
+	for i := range dd {
+		d := &dd[i]
+		ci := C.int(i)
+		d.Index = i
+		d.Name = C.GoString(C.SDL_GetDisplayName(ci))
+
+		var r C.SDL_Rect
+		if C.SDL_GetDisplayBounds(ci, &r) == 0 {
+			d.X, d.Y, d.Width, d.Height = int32(r.x), int32(r.y), int32(r.w), int32(r.h)
+		}
+
+		var dpi, h, v C.float
+		if C.SDL_GetDisplayDPI(ci, &dpi, &h, &v) == 0 {
+			d.DPI, d.HorizontalDPI, d.VerticalDPI = float32(dpi), float32(h), float32(v)
+		}
+
+		var m C.SDL_DisplayMode
+		if C.SDL_GetDesktopDisplayMode(ci, &m) == 0 {
+			d.Desktop = displayModeOf(&m)
+		}
+
+		nm := C.SDL_GetNumDisplayModes(ci)
+		for j := C.int(0); j < nm; j++ {
+			if C.SDL_GetDisplayMode(ci, j, &m) == 0 {
+				d.Modes = addMode(d.Modes, displayModeOf(&m))
+			}
+		}
+	}
+	return dd, nil
+}
+
+func displayModeOf(m *C.SDL_DisplayMode) DisplayMode {
+	return DisplayMode{
+		Width:       int32(m.w),
+		Height:      int32(m.h),
+		RefreshRate: int32(m.refresh_rate),
+	}
+}
+
+// addMode appends m to the list of modes, unless already present (SDL lists
+// the same mode once for each pixel format).
+func addMode(modes []DisplayMode, m DisplayMode) []DisplayMode {
+	for _, o := range modes {
+		if o == m {
+			return modes
+		}
+	}
+	return append(modes, m)
+}
+
+// WindowDisplay returns the index of the display containing the window.
+func WindowDisplay() int {
+	return int(C.SDL_GetWindowDisplayIndex(Window.window))
+}
+
+// SetExclusiveMode changes the display mode used by exclusive fullscreen. It
+// is applied immediately if the window is already in exclusive fullscreen.
+func SetExclusiveMode(m DisplayMode) error {
+	cm := C.SDL_DisplayMode{
+		w:            C.int(m.Width),
+		h:            C.int(m.Height),
+		refresh_rate: C.int(m.RefreshRate),
+	}
+	if C.SDL_SetWindowDisplayMode(Window.window, &cm) != 0 {
+		return Error("in exclusive fullscreen mode change", GetSDLError())
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,30 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+//------------------------------------------------------------------------------
+
+func TestAddMode(t *testing.T) {
+	var modes []DisplayMode
+	for _, m := range []DisplayMode{
+		{1920, 1080, 60},
+		{1920, 1080, 60},
+		{1920, 1080, 144},
+		{1280, 720, 60},
+		{1920, 1080, 60},
+	} {
+		modes = addMode(modes, m)
+	}
+	expected := []DisplayMode{{1920, 1080, 60}, {1920, 1080, 144}, {1280, 720, 60}}
+	if !reflect.DeepEqual(modes, expected) {
+		t.Errorf("got %v, expected %v", modes, expected)
+	}
+}
+
+//------------------------------------------------------------------------------
@@ -5,17 +5,9 @@ package internal
 
 //------------------------------------------------------------------------------
 
-type logger interface {
-	Print(v ...interface{})
-	Println(v ...interface{})
-	Printf(format string, v ...interface{})
-}
-
-type nolog struct{}
-
-func (nolog) Print(v ...interface{})                 {}
-func (nolog) Println(v ...interface{})               {}
-func (nolog) Printf(format string, v ...interface{}) {}
+import (
+	"strings"
+)
 
 //------------------------------------------------------------------------------
 
@@ -27,6 +19,8 @@ func Error(context string, err error) error {
 	return WrappedError{context, err}
 }
 
+// A WrappedError adds a context to an error. The original error is available
+// through errors.Is and errors.As.
 type WrappedError struct {
 	Context string
 	Err     error
@@ -36,4 +30,70 @@ func (e WrappedError) Error() string {
 	return e.Context + ": " + e.Err.Error()
 }
 
+// Unwrap returns the original error.
+func (e WrappedError) Unwrap() error {
+	return e.Err
+}
+
+//------------------------------------------------------------------------------
+
+// An ErrorList holds several errors. errors.Is and errors.As match any of them.
+type ErrorList []error
+
+func (l ErrorList) Error() string {
+	s := make([]string, len(l))
+	for i, e := range l {
+		s[i] = e.Error()
+	}
+	return strings.Join(s, "; ")
+}
+
+// Unwrap returns the errors of the list.
+func (l ErrorList) Unwrap() []error {
+	return l
+}
+
+//------------------------------------------------------------------------------
+
+// A StickyError records the errors of a subsystem until they are checked.
+// Depending on Config.CollectErrors, it keeps either the first one or all of
+// them; in the latter case, only the errors of the latest frame are kept, so
+// that the list doesn't grow when they are never checked.
+type StickyError struct {
+	errs  ErrorList
+	frame uint64 // of the collected errors
+}
+
+// Set records an error.
+func (s *StickyError) Set(err error) {
+	if err == nil {
+		return
+	}
+	if !Config.CollectErrors {
+		if len(s.errs) == 0 {
+			s.errs = append(s.errs, err)
+		}
+		return
+	}
+	if s.frame != FrameNumber {
+		s.errs = s.errs[:0]
+		s.frame = FrameNumber
+	}
+	s.errs = append(s.errs, err)
+}
+
+// Err returns the recorded errors (as an ErrorList if there is more than one),
+// and considers them checked.
+func (s *StickyError) Err() error {
+	l := s.errs
+	s.errs = nil
+	switch len(l) {
+	case 0:
+		return nil
+	case 1:
+		return l[0]
+	}
+	return l
+}
+
 //------------------------------------------------------------------------------
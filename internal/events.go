@@ -60,6 +60,28 @@ type GameLoop interface {
 	MouseButtonUp(b MouseButton, clicks int)
 	MouseWheel(deltaX, deltaY int32)
 
+	// Gamepad events
+	GamepadConnected(pad int)
+	GamepadDisconnected(pad int)
+	GamepadButtonDown(pad int, b GamepadButton)
+	GamepadButtonUp(pad int, b GamepadButton)
+	GamepadAxisMotion(pad int, a GamepadAxis, value float32)
+
+	// Text input events
+	TextInput(text string)
+	TextEditing(text string, start, length int)
+
+	// Drag and drop events
+	FileDropped(path string)
+	TextDropped(text string)
+
+	// Touch events
+	TouchDown(t Touch)
+	TouchMove(t Touch)
+	TouchUp(t Touch)
+	Pinch(scale float32, x, y int16)
+	Rotate(angle float32, x, y int16)
+
 	// Pixel events
 	ScreenResized(width, height int16, pixel int32)
 }
@@ -79,12 +101,14 @@ func ProcessEvents() {
 	}
 }
 
+// dispatch converts an SDL event and dispatches it.
 func dispatch(e unsafe.Pointer) {
-	ts := uint32(((*C.SDL_CommonEvent)(e)).timestamp)
-	VisibleNow = float64(ts) / 1000.0
+	ev := Event{
+		Time: float64(((*C.SDL_CommonEvent)(e)).timestamp) / 1000.0,
+	}
 	switch ((*C.SDL_CommonEvent)(e))._type {
 	case C.SDL_QUIT:
-		Loop.WindowQuit()
+		ev.Kind = EventQuit
 	// Window Events
 	case C.SDL_WINDOWEVENT:
 		e := (*C.SDL_WindowEvent)(e)
@@ -92,37 +116,33 @@ func dispatch(e unsafe.Pointer) {
 		case C.SDL_WINDOWEVENT_NONE:
 			// Ignore
 		case C.SDL_WINDOWEVENT_SHOWN:
-			Loop.WindowShown()
+			ev.Kind = EventWindowShown
 		case C.SDL_WINDOWEVENT_HIDDEN:
-			Loop.WindowHidden()
+			ev.Kind = EventWindowHidden
 		case C.SDL_WINDOWEVENT_EXPOSED:
 			// Ignore
 		case C.SDL_WINDOWEVENT_MOVED:
 			// Ignore
 		case C.SDL_WINDOWEVENT_RESIZED:
-			Window.Width, Window.Height = int32(e.data1), int32(e.data2)
-			ResizeScreen()
-			Loop.WindowResized(Window.Width, Window.Height)
+			// Ignore (always preceded by SIZE_CHANGED)
 		case C.SDL_WINDOWEVENT_SIZE_CHANGED:
-			//TODO
+			// Sent for all changes, including those made with SetWindowSize
+			ev.Kind = EventWindowResized
+			ev.X, ev.Y = int32(e.data1), int32(e.data2)
 		case C.SDL_WINDOWEVENT_MINIMIZED:
-			Loop.WindowMinimized()
+			ev.Kind = EventWindowMinimized
 		case C.SDL_WINDOWEVENT_MAXIMIZED:
-			Loop.WindowMaximized()
+			ev.Kind = EventWindowMaximized
 		case C.SDL_WINDOWEVENT_RESTORED:
-			Loop.WindowRestored()
+			ev.Kind = EventWindowRestored
 		case C.SDL_WINDOWEVENT_ENTER:
-			HasMouseFocus = true
-			Loop.WindowMouseEnter()
+			ev.Kind = EventWindowMouseEnter
 		case C.SDL_WINDOWEVENT_LEAVE:
-			HasMouseFocus = false
-			Loop.WindowMouseLeave()
+			ev.Kind = EventWindowMouseLeave
 		case C.SDL_WINDOWEVENT_FOCUS_GAINED:
-			HasFocus = true
-			Loop.WindowFocusGained()
+			ev.Kind = EventWindowFocusGained
 		case C.SDL_WINDOWEVENT_FOCUS_LOST:
-			HasFocus = false
-			Loop.WindowFocusLost()
+			ev.Kind = EventWindowFocusLost
 		case C.SDL_WINDOWEVENT_CLOSE:
 			// Ignore
 		default:
@@ -132,54 +152,76 @@ func dispatch(e unsafe.Pointer) {
 	case C.SDL_KEYDOWN:
 		e := (*C.SDL_KeyboardEvent)(e)
 		if e.repeat == 0 {
-			KeyState[e.keysym.scancode] = true
-			Loop.KeyDown(
-				KeyLabel(e.keysym.sym),
-				KeyPosition(e.keysym.scancode),
-			)
+			ev.Kind = EventKeyDown
+			ev.Label = KeyLabel(e.keysym.sym)
+			ev.Position = KeyPosition(e.keysym.scancode)
 		}
 	case C.SDL_KEYUP:
 		e := (*C.SDL_KeyboardEvent)(e)
-		KeyState[e.keysym.scancode] = false
-		Loop.KeyUp(
-			KeyLabel(e.keysym.sym),
-			KeyPosition(e.keysym.scancode),
-		)
+		ev.Kind = EventKeyUp
+		ev.Label = KeyLabel(e.keysym.sym)
+		ev.Position = KeyPosition(e.keysym.scancode)
+	// Text Input Events
+	case C.SDL_TEXTINPUT:
+		e := (*C.SDL_TextInputEvent)(e)
+		ev.Kind = EventTextInput
+		ev.Text = C.GoString(&e.text[0])
+	case C.SDL_TEXTEDITING:
+		e := (*C.SDL_TextEditingEvent)(e)
+		ev.Kind = EventTextEditing
+		ev.Text = C.GoString(&e.text[0])
+		ev.Start, ev.Length = int32(e.start), int32(e.length)
 	// Mouse Events
 	case C.SDL_MOUSEMOTION:
 		e := (*C.SDL_MouseMotionEvent)(e)
-		dx, dy := int32(e.xrel), int32(e.yrel)
-		MouseDeltaX += dx
-		MouseDeltaY += dy
-		MousePositionX, MousePositionY = int32(e.x), int32(e.y)
-		MouseButtons = uint32(e.state)
-		Loop.MouseMotion(
-			dx, dy,
-			MousePositionX, MousePositionY,
-		)
+		if e.which == C.SDL_TOUCH_MOUSEID {
+			break // Synthesized from touch events (see TouchMouse)
+		}
+		ev.Kind = EventMouseMotion
+		ev.DX, ev.DY = int32(e.xrel), int32(e.yrel)
+		ev.X, ev.Y = int32(e.x), int32(e.y)
+		ev.Buttons = uint32(e.state)
 	case C.SDL_MOUSEBUTTONDOWN:
 		e := (*C.SDL_MouseButtonEvent)(e)
-		MouseButtons |= 1 << (e.button - 1)
-		Loop.MouseButtonDown(
-			MouseButton(e.button),
-			int(e.clicks),
-		)
+		if e.which == C.SDL_TOUCH_MOUSEID {
+			break // Synthesized from touch events (see TouchMouse)
+		}
+		ev.Kind = EventMouseButtonDown
+		ev.Button = MouseButton(e.button)
+		ev.Clicks = int32(e.clicks)
 	case C.SDL_MOUSEBUTTONUP:
 		e := (*C.SDL_MouseButtonEvent)(e)
-		MouseButtons &= ^(1 << (e.button - 1))
-		Loop.MouseButtonUp(
-			MouseButton(e.button),
-			int(e.clicks),
-		)
+		if e.which == C.SDL_TOUCH_MOUSEID {
+			break // Synthesized from touch events (see TouchMouse)
+		}
+		ev.Kind = EventMouseButtonUp
+		ev.Button = MouseButton(e.button)
+		ev.Clicks = int32(e.clicks)
 	case C.SDL_MOUSEWHEEL:
 		e := (*C.SDL_MouseWheelEvent)(e)
 		var d int32 = 1
 		if e.direction == C.SDL_MOUSEWHEEL_FLIPPED {
 			d = -1
 		}
-		Loop.MouseWheel(
-			int32(e.x)*d, int32(e.y)*d,
-		)
+		ev.Kind = EventMouseWheel
+		ev.DX, ev.DY = int32(e.x)*d, int32(e.y)*d
+	// Touch Events
+	case C.SDL_FINGERDOWN, C.SDL_FINGERMOTION, C.SDL_FINGERUP:
+		e := (*C.SDL_TouchFingerEvent)(e)
+		switch e._type {
+		case C.SDL_FINGERDOWN:
+			ev.Kind = EventTouchDown
+		case C.SDL_FINGERMOTION:
+			ev.Kind = EventTouchMove
+			ev.TouchDX, ev.TouchDY = float32(e.dx), float32(e.dy)
+		case C.SDL_FINGERUP:
+			ev.Kind = EventTouchUp
+		}
+		ev.Finger = int64(e.fingerId)
+		ev.TouchX, ev.TouchY = float32(e.x), float32(e.y)
+		ev.Pressure = float32(e.pressure)
+	case C.SDL_MULTIGESTURE, C.SDL_DOLLARGESTURE, C.SDL_DOLLARRECORD:
+		// Ignore: gestures are recognized from the touch events
 	//TODO: Joystick Events
 	case C.SDL_JOYAXISMOTION:
 	case C.SDL_JOYBALLMOTION:
@@ -188,19 +230,65 @@ func dispatch(e unsafe.Pointer) {
 	case C.SDL_JOYBUTTONUP:
 	case C.SDL_JOYDEVICEADDED:
 	case C.SDL_JOYDEVICEREMOVED:
-	//TODO: Controller Events
+	// Controller Events
 	case C.SDL_CONTROLLERAXISMOTION:
+		e := (*C.SDL_ControllerAxisEvent)(e)
+		if p := gamepadSlot(e.which); p >= 0 {
+			ev.Kind = EventGamepadAxisMotion
+			ev.Gamepad = p
+			ev.PadAxis = GamepadAxis(e.axis)
+			ev.Value = int16(e.value)
+		}
 	case C.SDL_CONTROLLERBUTTONDOWN:
+		e := (*C.SDL_ControllerButtonEvent)(e)
+		if p := gamepadSlot(e.which); p >= 0 {
+			ev.Kind = EventGamepadButtonDown
+			ev.Gamepad = p
+			ev.PadButton = GamepadButton(e.button)
+		}
 	case C.SDL_CONTROLLERBUTTONUP:
+		e := (*C.SDL_ControllerButtonEvent)(e)
+		if p := gamepadSlot(e.which); p >= 0 {
+			ev.Kind = EventGamepadButtonUp
+			ev.Gamepad = p
+			ev.PadButton = GamepadButton(e.button)
+		}
 	case C.SDL_CONTROLLERDEVICEADDED:
+		e := (*C.SDL_ControllerDeviceEvent)(e)
+		if p := gamepadOpen(C.int(e.which)); p >= 0 {
+			ev.Kind = EventGamepadConnected
+			ev.Gamepad = p
+		}
 	case C.SDL_CONTROLLERDEVICEREMOVED:
+		e := (*C.SDL_ControllerDeviceEvent)(e)
+		if p := gamepadClose(C.SDL_JoystickID(e.which)); p >= 0 {
+			ev.Kind = EventGamepadDisconnected
+			ev.Gamepad = p
+		}
 	case C.SDL_CONTROLLERDEVICEREMAPPED:
+		// Ignore
+	// Drag and Drop Events
+	case C.SDL_DROPFILE:
+		e := (*C.SDL_DropEvent)(e)
+		ev.Kind = EventFileDropped
+		ev.Text = C.GoString(e.file)
+		C.SDL_free(unsafe.Pointer(e.file))
+	case C.SDL_DROPTEXT:
+		e := (*C.SDL_DropEvent)(e)
+		ev.Kind = EventTextDropped
+		ev.Text = C.GoString(e.file)
+		C.SDL_free(unsafe.Pointer(e.file))
+	case C.SDL_DROPBEGIN, C.SDL_DROPCOMPLETE:
+		// Ignore
 	//TODO: Audio Device Events
 	case C.SDL_AUDIODEVICEADDED:
 	case C.SDL_AUDIODEVICEREMOVED:
 	default:
 		//TODO: log.Print("unknown SDL event:", ((*C.SDL_CommonEvent)(e))._type)
 	}
+	if ev.Kind != EventNone && (FilterEvent == nil || FilterEvent(ev)) {
+		Dispatch(ev)
+	}
 }
 
 // peepEvents fill the event buffer and returns the number of events fetched.
@@ -0,0 +1,197 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package internal
+
+//------------------------------------------------------------------------------
+
+/*
+#include "sdl.h"
+*/
+import "C"
+
+//------------------------------------------------------------------------------
+
+// A GamepadButton designates a button using the standard controller mapping.
+type GamepadButton uint8
+
+// A GamepadAxis designates an axis using the standard controller mapping.
+type GamepadAxis uint8
+
+// Sizes of the gamepad state tables.
+const (
+	GamepadMax        = 8
+	GamepadButtonsMax = 21
+	GamepadAxesMax    = 6
+)
+
+// GamepadState holds the state of one gamepad slot.
+type GamepadState struct {
+	Connected bool
+	Name      string
+	Buttons   [GamepadButtonsMax]bool
+	Axes      [GamepadAxesMax]int16
+}
+
+// Gamepads holds the state of all gamepad slots. Each connected controller is
+// assigned the first free slot.
+var Gamepads [GamepadMax]GamepadState
+
+// gamepads holds the SDL game controllers, indexed by slot.
+var gamepads [GamepadMax]*C.SDL_GameController
+
+//------------------------------------------------------------------------------
+
+// gamepadOpen opens the game controller at an SDL device index, and returns
+// its slot, or -1 if it can't be opened.
+func gamepadOpen(device C.int) int32 {
+	if C.SDL_IsGameController(device) != C.SDL_TRUE {
+		return -1
+	}
+	for p := range gamepads {
+		if gamepads[p] == nil {
+			c := C.SDL_GameControllerOpen(device)
+			if c == nil {
+				Debug.Printf("unable to open gamepad: %s", GetSDLError())
+				return -1
+			}
+			gamepads[p] = c
+			Gamepads[p].Name = C.GoString(C.SDL_GameControllerName(c))
+			return int32(p)
+		}
+	}
+	Debug.Printf("unable to open gamepad: too many gamepads")
+	return -1
+}
+
+// gamepadClose closes the game controller with an SDL instance ID, and
+// returns its slot, or -1 if it wasn't opened.
+func gamepadClose(instance C.SDL_JoystickID) int32 {
+	p := gamepadSlot(instance)
+	if p >= 0 {
+		C.SDL_GameControllerClose(gamepads[p])
+		gamepads[p] = nil
+	}
+	return p
+}
+
+// gamepadSlot returns the slot of the game controller with an SDL instance ID,
+// or -1 if it isn't opened.
+func gamepadSlot(instance C.SDL_JoystickID) int32 {
+	for p, c := range gamepads {
+		if c != nil && C.SDL_JoystickInstanceID(C.SDL_GameControllerGetJoystick(c)) == instance {
+			return int32(p)
+		}
+	}
+	return -1
+}
+
+//------------------------------------------------------------------------------
+
+// GamepadRumble starts a rumble effect on a gamepad. The intensities are
+// between 0 and 1, and the duration is in seconds.
+func GamepadRumble(pad int, low, high float32, duration float64) error {
+	if pad < 0 || pad >= GamepadMax || gamepads[pad] == nil {
+		return nil
+	}
+	errcode := C.SDL_GameControllerRumble(
+		gamepads[pad],
+		C.Uint16(rumbleIntensity(low)),
+		C.Uint16(rumbleIntensity(high)),
+		C.Uint32(rumbleDuration(duration)),
+	)
+	if errcode != 0 {
+		return Error("in gamepad rumble", GetSDLError())
+	}
+	return nil
+}
+
+// rumbleIntensity converts an intensity to the SDL range, clamping it to
+// [0, 1] first (NaN counts as 0).
+func rumbleIntensity(i float32) uint16 {
+	switch {
+	case !(i > 0):
+		return 0
+	case i >= 1:
+		return 0xFFFF
+	}
+	return uint16(i * 0xFFFF)
+}
+
+// rumbleDuration converts a duration in seconds to milliseconds, clamping it
+// to the SDL range.
+func rumbleDuration(d float64) uint32 {
+	switch {
+	case !(d > 0):
+		return 0
+	case d*1000 >= 0xFFFFFFFF:
+		return 0xFFFFFFFF
+	}
+	return uint32(d * 1000)
+}
+
+//------------------------------------------------------------------------------
+
+// A VirtualGamepad is a game controller simulated by SDL, used for testing.
+type VirtualGamepad struct {
+	device   C.int
+	joystick *C.SDL_Joystick
+}
+
+// GamepadAttachVirtual creates a virtual game controller. It is reported to
+// the game loop like a real device.
+func GamepadAttachVirtual() (*VirtualGamepad, error) {
+	// Needed in headless runs. The subsystem stays initialized after Detach,
+	// since the controller is only closed when the removal is dispatched.
+	if C.SDL_InitSubSystem(C.SDL_INIT_GAMECONTROLLER) != 0 {
+		return nil, Error("in virtual gamepad creation", GetSDLError())
+	}
+	d := C.SDL_JoystickAttachVirtual(
+		C.SDL_JOYSTICK_TYPE_GAMECONTROLLER,
+		GamepadAxesMax, GamepadButtonsMax, 0,
+	)
+	if d < 0 {
+		err := Error("in virtual gamepad creation", GetSDLError())
+		C.SDL_QuitSubSystem(C.SDL_INIT_GAMECONTROLLER)
+		return nil, err
+	}
+	j := C.SDL_JoystickOpen(d)
+	if j == nil {
+		err := Error("in virtual gamepad opening", GetSDLError())
+		C.SDL_JoystickDetachVirtual(d)
+		C.SDL_QuitSubSystem(C.SDL_INIT_GAMECONTROLLER)
+		return nil, err
+	}
+	return &VirtualGamepad{device: d, joystick: j}, nil
+}
+
+// SetButton changes the state of a button of the virtual gamepad.
+func (v *VirtualGamepad) SetButton(b GamepadButton, pressed bool) error {
+	var s C.Uint8
+	if pressed {
+		s = 1
+	}
+	if C.SDL_JoystickSetVirtualButton(v.joystick, C.int(b), s) != 0 {
+		return Error("in virtual gamepad", GetSDLError())
+	}
+	return nil
+}
+
+// SetAxis changes the value of an axis of the virtual gamepad.
+func (v *VirtualGamepad) SetAxis(a GamepadAxis, value int16) error {
+	if C.SDL_JoystickSetVirtualAxis(v.joystick, C.int(a), C.Sint16(value)) != 0 {
+		return Error("in virtual gamepad", GetSDLError())
+	}
+	return nil
+}
+
+// Detach removes the virtual gamepad.
+func (v *VirtualGamepad) Detach() error {
+	C.SDL_JoystickClose(v.joystick)
+	if C.SDL_JoystickDetachVirtual(v.device) != 0 {
+		return Error("in virtual gamepad removal", GetSDLError())
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,33 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package internal
+
+import (
+	"math"
+	"testing"
+)
+
+//------------------------------------------------------------------------------
+
+func TestRumbleRanges(t *testing.T) {
+	nan := float32(math.NaN())
+	for _, tt := range []struct {
+		in   float32
+		want uint16
+	}{{-1, 0}, {nan, 0}, {0, 0}, {0.5, 0x7FFF}, {1, 0xFFFF}, {3, 0xFFFF}} {
+		if got := rumbleIntensity(tt.in); got != tt.want {
+			t.Errorf("intensity %g: got %#x, expected %#x", tt.in, got, tt.want)
+		}
+	}
+	for _, tt := range []struct {
+		in   float64
+		want uint32
+	}{{-2, 0}, {math.NaN(), 0}, {0.25, 250}, {1e9, 0xFFFFFFFF}} {
+		if got := rumbleDuration(tt.in); got != tt.want {
+			t.Errorf("duration %g: got %d, expected %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
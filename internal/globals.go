@@ -12,13 +12,6 @@ import "C"
 
 //------------------------------------------------------------------------------
 
-import (
-	"log"
-	"os"
-)
-
-//------------------------------------------------------------------------------
-
 // Path of the executable (uses os-dependant separator).
 var FilePath string
 
@@ -27,8 +20,8 @@ var Path string
 
 //------------------------------------------------------------------------------
 
-// Config holds the initial configuration of the game.
-var Config = struct {
+// Configuration describes the setup of the game window and pixel screen.
+type Configuration struct {
 	Debug          bool
 	Title          string
 	WindowSize     [2]int32
@@ -38,10 +31,19 @@ var Config = struct {
 	Multisample    int
 	Display        int
 	Fullscreen     bool
-	FullscreenMode string
+	FullscreenMode string // "Desktop" or "Exclusive"
 	VSync          bool
 	PaletteAuto    bool
-}{
+	CollectErrors  bool
+	TouchMouse     bool
+}
+
+// Config holds the configuration of the game.
+var Config = DefaultConfig
+
+// DefaultConfig is the configuration used when no option is given, and no
+// configuration file is found.
+var DefaultConfig = Configuration{
 	Debug:          false,
 	Title:          "Carol",
 	WindowSize:     [2]int32{1280, 720},
@@ -54,13 +56,18 @@ var Config = struct {
 	FullscreenMode: "Desktop",
 	VSync:          true,
 	PaletteAuto:    true,
+	CollectErrors:  false,
+	TouchMouse:     true,
 }
 
 //------------------------------------------------------------------------------
 
+// The loggers of the core subsystem.
 var (
-	Log   logger = log.New(os.Stderr, "", log.Ltime|log.Lmicroseconds)
-	Debug logger = nolog{}
+	Debug    = Logger{System: "carol", Level: DebugLevel}
+	Log      = Logger{System: "carol", Level: InfoLevel}
+	Warning  = Logger{System: "carol", Level: WarningLevel}
+	ErrorLog = Logger{System: "carol", Level: ErrorLevel}
 )
 
 //------------------------------------------------------------------------------
@@ -75,6 +82,9 @@ var (
 // It shouldn't be used outside of these three contexts.
 var VisibleNow float64
 
+// FrameNumber counts the frames since the start of the game loop.
+var FrameNumber uint64
+
 //------------------------------------------------------------------------------
 
 // QuitRequested makes the game loop stop if true.
@@ -82,6 +92,15 @@ var QuitRequested = false
 
 //------------------------------------------------------------------------------
 
+// Headless is true when the game loop runs without window nor OpenGL context.
+var Headless = false
+
+// Offscreen is true when the game loop runs with a hidden window, and renders
+// only to the offscreen framebuffer.
+var Offscreen = false
+
+//------------------------------------------------------------------------------
+
 // Window is the game window.
 var Window struct {
 	window        *C.SDL_Window
@@ -118,11 +137,25 @@ var MousePositionX, MousePositionY int32
 // MouseButtons holds the state of the mouse buttons.
 var MouseButtons uint32
 
+// MouseWheel holds the wheel motion since the previous Update step.
+var MouseWheelX, MouseWheelY int32
+
 //------------------------------------------------------------------------------
 
 var PixelSetup = func() error { return nil }
 var PixelDraw = func() error { return nil }
+var TweenStep = func() {}
+var TweenQuit = func() {}
+var ScriptStep = func() error { return nil }
+var ScriptQuit = func() {}
+var TextSetup = func() error { return nil }
+var AudioSetup = func() error { return nil }
+var AudioQuit = func() {}
+var SaveScreenshot = func() {}
 
 var ResizeScreen = func() {}
 
+// WindowToScreen converts window coordinates to screen pixels.
+var WindowToScreen = func(x, y int32) (int16, int16) { return int16(x), int16(y) }
+
 //------------------------------------------------------------------------------
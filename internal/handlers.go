@@ -63,13 +63,18 @@ func (h Handlers) MouseWheel(dx, dy int32) {}
 
 //------------------------------------------------------------------------------
 
-// KeyDown requests the game loop to stop if Escape is pressed.
+// KeyDown requests the game loop to stop if Escape is pressed, and toggles
+// fullscreen with F11. In debug mode, F12 saves a screenshot.
 func (h Handlers) KeyDown(l KeyLabel, p KeyPosition) {
 	switch l {
 	case '\033': // key.LabelEscape
 		QuitRequested = true
 	case (1 << 30) | 68: // key.LabelF11
 		ToggleFullscreen()
+	case (1 << 30) | 69: // key.LabelF12
+		if Config.Debug {
+			SaveScreenshot()
+		}
 	}
 }
 
@@ -79,6 +84,56 @@ func (h Handlers) KeyUp(l KeyLabel, p KeyPosition) {
 
 //------------------------------------------------------------------------------
 
+// TextInput does nothing.
+func (h Handlers) TextInput(text string) {}
+
+// TextEditing does nothing.
+func (h Handlers) TextEditing(text string, start, length int) {}
+
+//------------------------------------------------------------------------------
+
+// FileDropped does nothing.
+func (h Handlers) FileDropped(path string) {}
+
+// TextDropped does nothing.
+func (h Handlers) TextDropped(text string) {}
+
+//------------------------------------------------------------------------------
+
+// GamepadConnected does nothing.
+func (h Handlers) GamepadConnected(pad int) {}
+
+// GamepadDisconnected does nothing.
+func (h Handlers) GamepadDisconnected(pad int) {}
+
+// GamepadButtonDown does nothing.
+func (h Handlers) GamepadButtonDown(pad int, b GamepadButton) {}
+
+// GamepadButtonUp does nothing.
+func (h Handlers) GamepadButtonUp(pad int, b GamepadButton) {}
+
+// GamepadAxisMotion does nothing.
+func (h Handlers) GamepadAxisMotion(pad int, a GamepadAxis, value float32) {}
+
+//------------------------------------------------------------------------------
+
+// TouchDown does nothing.
+func (h Handlers) TouchDown(t Touch) {}
+
+// TouchMove does nothing.
+func (h Handlers) TouchMove(t Touch) {}
+
+// TouchUp does nothing.
+func (h Handlers) TouchUp(t Touch) {}
+
+// Pinch does nothing.
+func (h Handlers) Pinch(scale float32, x, y int16) {}
+
+// Rotate does nothing.
+func (h Handlers) Rotate(angle float32, x, y int16) {}
+
+//------------------------------------------------------------------------------
+
 func (h Handlers) ScreenResized(width, height int16, pixel int32) {}
 
 //------------------------------------------------------------------------------
@@ -0,0 +1,89 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package internal
+
+//------------------------------------------------------------------------------
+
+// KeyJustPressed and KeyJustReleased hold, for the current Update step, the
+// keys that were pressed or released since the previous step.
+var KeyJustPressed, KeyJustReleased [512]bool
+
+// KeyPressTime holds the time of the step where each key was last pressed.
+var KeyPressTime [512]float64
+
+// MouseJustPressed and MouseJustReleased hold, for the current Update step,
+// the mouse buttons pressed or released since the previous step.
+var MouseJustPressed, MouseJustReleased uint32
+
+// MousePressTime holds the time of the step where each button was last
+// pressed.
+var MousePressTime [32]float64
+
+// pending edges, collected by Dispatch until the next step.
+var pending struct {
+	keyPressed, keyReleased     [512]bool
+	mousePressed, mouseReleased uint32
+	wheelX, wheelY              int32
+}
+
+//------------------------------------------------------------------------------
+
+// LatchInput must be called before each Update step: it makes the input edges
+// collected since the previous step visible to the step starting at time now.
+//
+// Edges are accumulated rather than sampled, so a key pressed and released
+// between two steps is reported as both just pressed and just released.
+func LatchInput(now float64) {
+	KeyJustPressed = pending.keyPressed
+	KeyJustReleased = pending.keyReleased
+	for p, ok := range KeyJustPressed {
+		if ok {
+			KeyPressTime[p] = now
+		}
+	}
+
+	MouseJustPressed = pending.mousePressed
+	MouseJustReleased = pending.mouseReleased
+	for b := range MousePressTime {
+		if MouseJustPressed&(1<<uint(b)) != 0 {
+			MousePressTime[b] = now
+		}
+	}
+
+	MouseWheelX, MouseWheelY = pending.wheelX, pending.wheelY
+
+	pending.keyPressed = [512]bool{}
+	pending.keyReleased = [512]bool{}
+	pending.mousePressed, pending.mouseReleased = 0, 0
+	pending.wheelX, pending.wheelY = 0, 0
+}
+
+//------------------------------------------------------------------------------
+
+// KeyDuration returns the time since the key at position p was pressed, if it
+// is currently held down or has just been released, or 0 otherwise.
+func KeyDuration(p KeyPosition) float64 {
+	if int(p) >= len(KeyState) {
+		return 0
+	}
+	if pending.keyPressed[p] || !(KeyState[p] || KeyJustReleased[p]) {
+		return 0
+	}
+	return VisibleNow - KeyPressTime[p]
+}
+
+// MouseDuration returns the time since button b was pressed, if it is
+// currently held down or has just been released, or 0 otherwise.
+func MouseDuration(b MouseButton) float64 {
+	if b < 1 || int(b) > len(MousePressTime) {
+		return 0
+	}
+	var m uint32 = 1 << (b - 1)
+	if pending.mousePressed&m != 0 || (MouseButtons|MouseJustReleased)&m == 0 {
+		return 0
+	}
+	return VisibleNow - MousePressTime[b-1]
+}
+
+//------------------------------------------------------------------------------
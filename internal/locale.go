@@ -0,0 +1,58 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package internal
+
+//------------------------------------------------------------------------------
+
+import (
+	"os"
+	"strings"
+	"unsafe"
+)
+
+//------------------------------------------------------------------------------
+
+/*
+#include "sdl.h"
+*/
+import "C"
+
+//------------------------------------------------------------------------------
+
+// SystemLocales returns the locales preferred by the user, in order of
+// preference, as BCP 47 tags (e.g. "fr-CA").
+func SystemLocales() []string {
+	var l []string
+
+	sl := C.SDL_GetPreferredLocales()
+	if sl != nil {
+		defer C.SDL_free(unsafe.Pointer(sl))
+		for p := sl; p.language != nil; p = (*C.SDL_Locale)(unsafe.Pointer(uintptr(unsafe.Pointer(p)) + unsafe.Sizeof(*p))) {
+			t := C.GoString(p.language)
+			if p.country != nil {
+				t += "-" + C.GoString(p.country)
+			}
+			l = append(l, t)
+		}
+	}
+
+	if len(l) == 0 {
+		// POSIX environment variables, e.g. "fr_CA.UTF-8"
+		for _, v := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+			e := os.Getenv(v)
+			if e == "" || e == "C" || e == "POSIX" {
+				continue
+			}
+			if i := strings.IndexAny(e, ".@"); i >= 0 {
+				e = e[:i]
+			}
+			l = append(l, strings.Replace(e, "_", "-", 1))
+			break
+		}
+	}
+
+	return l
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,198 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package internal
+
+//------------------------------------------------------------------------------
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+//------------------------------------------------------------------------------
+
+// A LogLevel is the severity of a log message.
+type LogLevel uint8
+
+// The log levels, by increasing severity.
+const (
+	DebugLevel LogLevel = iota
+	InfoLevel
+	WarningLevel
+	ErrorLevel
+)
+
+var logLevelNames = [...]string{
+	DebugLevel:   "DEBUG",
+	InfoLevel:    "INFO",
+	WarningLevel: "WARNING",
+	ErrorLevel:   "ERROR",
+}
+
+// String returns the name of the level.
+func (l LogLevel) String() string {
+	if int(l) < len(logLevelNames) {
+		return logLevelNames[l]
+	}
+	return "UNKNOWN"
+}
+
+//------------------------------------------------------------------------------
+
+// A LogEntry is a message kept in the log history.
+type LogEntry struct {
+	Time    time.Time
+	Level   LogLevel
+	System  string
+	Message string
+}
+
+// LogHistory is the number of messages kept in the log history.
+const LogHistory = 256
+
+// LogOutput is where the log messages are written.
+var LogOutput io.Writer = os.Stderr
+
+var logState = struct {
+	sync.Mutex
+	levels   map[string]LogLevel
+	fallback LogLevel
+	explicit bool
+	history  [LogHistory]LogEntry
+	next     int
+	count    int
+}{
+	levels:   map[string]LogLevel{},
+	fallback: InfoLevel,
+}
+
+//------------------------------------------------------------------------------
+
+// A Logger writes messages for a subsystem (e.g. "pixel"), at a given level.
+// The messages are only written if the level is enabled for the subsystem.
+type Logger struct {
+	System string
+	Level  LogLevel
+}
+
+// Enabled returns true if the messages of the logger are written.
+func (l Logger) Enabled() bool {
+	return l.Level >= LogLevelOf(l.System)
+}
+
+// Print writes a message, formatted like fmt.Sprint.
+func (l Logger) Print(v ...interface{}) {
+	if l.Enabled() {
+		logWrite(l, fmt.Sprint(v...))
+	}
+}
+
+// Println writes a message, formatted like fmt.Sprintln.
+func (l Logger) Println(v ...interface{}) {
+	if l.Enabled() {
+		logWrite(l, fmt.Sprintln(v...))
+	}
+}
+
+// Printf writes a message, formatted like fmt.Sprintf.
+func (l Logger) Printf(format string, v ...interface{}) {
+	if l.Enabled() {
+		logWrite(l, fmt.Sprintf(format, v...))
+	}
+}
+
+func logWrite(l Logger, m string) {
+	m = strings.TrimRight(m, "\n")
+	t := time.Now()
+
+	logState.Lock()
+	defer logState.Unlock()
+
+	logState.history[logState.next] = LogEntry{
+		Time:    t,
+		Level:   l.Level,
+		System:  l.System,
+		Message: m,
+	}
+	logState.next = (logState.next + 1) % LogHistory
+	if logState.count < LogHistory {
+		logState.count++
+	}
+
+	fmt.Fprintf(LogOutput, "%s %s %s: %s\n", t.Format("15:04:05.000000"), l.Level, l.System, m)
+}
+
+//------------------------------------------------------------------------------
+
+// SetLogLevel changes the minimum level of the messages written for a
+// subsystem, or for all subsystems without their own level if system is
+// empty.
+func SetLogLevel(system string, l LogLevel) {
+	logState.Lock()
+	defer logState.Unlock()
+	if system == "" {
+		logState.fallback = l
+		logState.explicit = true
+		return
+	}
+	logState.levels[system] = l
+}
+
+// SetLogDefault changes the minimum level of the messages written for all
+// subsystems without their own level, unless it has been explicitly set.
+func SetLogDefault(l LogLevel) {
+	logState.Lock()
+	defer logState.Unlock()
+	if !logState.explicit {
+		logState.fallback = l
+	}
+}
+
+// SaveLogLevels returns a function that restores the current log levels (used
+// by the tests).
+func SaveLogLevels() (restore func()) {
+	logState.Lock()
+	defer logState.Unlock()
+	levels := make(map[string]LogLevel, len(logState.levels))
+	for s, l := range logState.levels {
+		levels[s] = l
+	}
+	fallback, explicit := logState.fallback, logState.explicit
+	return func() {
+		logState.Lock()
+		defer logState.Unlock()
+		logState.levels = levels
+		logState.fallback = fallback
+		logState.explicit = explicit
+	}
+}
+
+// LogLevelOf returns the minimum level of the messages written for a
+// subsystem.
+func LogLevelOf(system string) LogLevel {
+	logState.Lock()
+	defer logState.Unlock()
+	l, ok := logState.levels[system]
+	if !ok {
+		return logState.fallback
+	}
+	return l
+}
+
+// RecentLog returns the last messages written, from the oldest.
+func RecentLog() []LogEntry {
+	logState.Lock()
+	defer logState.Unlock()
+	h := make([]LogEntry, logState.count)
+	for i := range h {
+		h[i] = logState.history[(logState.next-logState.count+i+LogHistory)%LogHistory]
+	}
+	return h
+}
+
+//------------------------------------------------------------------------------
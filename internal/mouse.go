@@ -26,10 +26,10 @@ func MouseSetRelativeMode(enabled bool) error {
 		C.SDL_ShowCursor(C.SDL_DISABLE)
 	}
 	if C.SDL_SetRelativeMouseMode(m) != 0 {
-		C.SDL_ShowCursor(C.SDL_ENABLE)
+		cursorApply()
 		return Error("setting relative mouse mode", GetSDLError())
 	}
-	C.SDL_ShowCursor(C.SDL_ENABLE)
+	cursorApply()
 	return nil
 }
 
@@ -6,8 +6,6 @@ package internal
 //------------------------------------------------------------------------------
 
 import (
-	"encoding/json"
-	"log"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -40,21 +38,17 @@ func init() {
 //------------------------------------------------------------------------------
 
 func Setup() error {
-	// Load config file
+	// Load configuration
 
-	f, err := os.Open(Path + "init.json")
+	err := LoadConfig()
 	if err != nil {
-		return Error(`in configuration file "init.json" opening`, err)
-	}
-	d := json.NewDecoder(f)
-	if err := d.Decode(&Config); err != nil {
-		return Error(`in configuration file "init.json" parsing`, err)
+		return err
 	}
 
 	// Setup logger
 
 	if Config.Debug {
-		Debug = log.New(os.Stderr, "", log.Ltime|log.Lmicroseconds|log.Lshortfile)
+		SetLogDefault(DebugLevel)
 	}
 
 	// Check config
@@ -92,3 +86,34 @@ func Setup() error {
 }
 
 //------------------------------------------------------------------------------
+
+// SetupOffscreen initializes SDL and opens a hidden window, for headless runs
+// that need an OpenGL context. The configuration file is not loaded.
+func SetupOffscreen() error {
+	if Config.Debug {
+		SetLogDefault(DebugLevel)
+	}
+
+	if errcode := C.SDL_Init(C.SDL_INIT_VIDEO); errcode != 0 {
+		return Error("in SDL initalization", GetSDLError())
+	}
+
+	Offscreen = true
+	err := OpenWindow(
+		Config.Title,
+		Config.WindowSize[0],
+		Config.WindowSize[1],
+		Config.Display,
+		false,
+		Config.FullscreenMode,
+		false,
+		Config.Debug,
+	)
+	if err != nil {
+		return Error("in offscreen window opening", err)
+	}
+
+	return nil
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,44 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package internal
+
+//------------------------------------------------------------------------------
+
+/*
+#include "sdl.h"
+*/
+import "C"
+
+//------------------------------------------------------------------------------
+
+// TextInputStart enables the text input events, and sets the rectangle (in
+// window coordinates) where the text is being typed, as a hint for IME
+// candidate lists.
+func TextInputStart(x, y, w, h int32) {
+	TextInputSetRect(x, y, w, h)
+	C.SDL_StartTextInput()
+}
+
+// TextInputSetRect changes the rectangle hint used by IME.
+func TextInputSetRect(x, y, w, h int32) {
+	r := C.SDL_Rect{
+		x: C.int(x),
+		y: C.int(y),
+		w: C.int(w),
+		h: C.int(h),
+	}
+	C.SDL_SetTextInputRect(&r)
+}
+
+// TextInputStop disables the text input events.
+func TextInputStop() {
+	C.SDL_StopTextInput()
+}
+
+// TextInputActive returns true if the text input events are enabled.
+func TextInputActive() bool {
+	return C.SDL_IsTextInputActive() == C.SDL_TRUE
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,209 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package internal
+
+//------------------------------------------------------------------------------
+
+import (
+	"math"
+)
+
+//------------------------------------------------------------------------------
+
+// A Touch describes a finger on a touch screen.
+type Touch struct {
+	Finger           int64   // identifies the finger while it touches the screen
+	X, Y             float32 // normalized position, from 0 to 1
+	DX, DY           float32 // normalized motion (only in TouchMove)
+	Pressure         float32 // normalized pressure, from 0 to 1
+	ScreenX, ScreenY int16   // position in screen pixels
+}
+
+// Touches holds the fingers currently on the screen, in order of arrival.
+var Touches []Touch
+
+// touchMouse is true while the first finger is used to synthesize mouse
+// events.
+var touchMouse struct {
+	active bool
+	finger int64
+}
+
+//------------------------------------------------------------------------------
+
+func touchOf(e Event) Touch {
+	t := Touch{
+		Finger:   e.Finger,
+		X:        e.TouchX,
+		Y:        e.TouchY,
+		DX:       e.TouchDX,
+		DY:       e.TouchDY,
+		Pressure: e.Pressure,
+	}
+	t.ScreenX, t.ScreenY = WindowToScreen(t.windowPosition())
+	return t
+}
+
+func (t Touch) windowPosition() (x, y int32) {
+	return int32(t.X * float32(Window.Width)), int32(t.Y * float32(Window.Height))
+}
+
+func touchIndex(finger int64) int {
+	for i := range Touches {
+		if Touches[i].Finger == finger {
+			return i
+		}
+	}
+	return -1
+}
+
+//------------------------------------------------------------------------------
+
+func touchDown(e Event) {
+	t := touchOf(e)
+	if i := touchIndex(t.Finger); i >= 0 {
+		Touches[i] = t
+	} else {
+		Touches = append(Touches, t)
+	}
+	Loop.TouchDown(t)
+
+	if Config.TouchMouse && len(Touches) == 1 {
+		touchMouse.active = true
+		touchMouse.finger = t.Finger
+		touchMouseMotion(e.Time, t)
+		handle(Event{Kind: EventMouseButtonDown, Time: e.Time, Button: 1, Clicks: 1})
+	}
+
+	if !gesture.active {
+		startGesture()
+	}
+}
+
+func touchMove(e Event) {
+	t := touchOf(e)
+	i := touchIndex(t.Finger)
+	if i < 0 {
+		return
+	}
+	Touches[i] = t
+	Loop.TouchMove(t)
+
+	if touchMouse.active && touchMouse.finger == t.Finger {
+		touchMouseMotion(e.Time, t)
+	}
+
+	updateGesture()
+}
+
+func touchUp(e Event) {
+	t := touchOf(e)
+	i := touchIndex(t.Finger)
+	if i < 0 {
+		return
+	}
+	Touches = append(Touches[:i], Touches[i+1:]...)
+	Loop.TouchUp(t)
+
+	if touchMouse.active && touchMouse.finger == t.Finger {
+		touchMouse.active = false
+		handle(Event{Kind: EventMouseButtonUp, Time: e.Time, Button: 1, Clicks: 1})
+	}
+
+	if gesture.active && (t.Finger == gesture.a || t.Finger == gesture.b) {
+		startGesture()
+	}
+}
+
+func touchMouseMotion(time float64, t Touch) {
+	x, y := t.windowPosition()
+	handle(Event{
+		Kind:    EventMouseMotion,
+		Time:    time,
+		X:       x,
+		Y:       y,
+		DX:      x - MousePositionX,
+		DY:      y - MousePositionY,
+		Buttons: MouseButtons,
+	})
+}
+
+//------------------------------------------------------------------------------
+
+// Minimum changes needed to recognize a gesture. Once recognized, every change
+// is reported.
+const (
+	pinchThreshold  = 0.05 // relative change of the distance between fingers
+	rotateThreshold = 0.1  // in radians
+)
+
+// gesture tracks the first two fingers on the screen.
+var gesture struct {
+	active             bool
+	a, b               int64
+	dist0, angle0      float32 // at the start of the gesture
+	dist, angle        float32 // at the last report
+	pinching, rotating bool
+}
+
+func startGesture() {
+	gesture.active, gesture.pinching, gesture.rotating = false, false, false
+	if len(Touches) < 2 {
+		return
+	}
+	gesture.active = true
+	gesture.a, gesture.b = Touches[0].Finger, Touches[1].Finger
+	gesture.dist0, gesture.angle0 = gestureShape()
+	gesture.dist, gesture.angle = gesture.dist0, gesture.angle0
+}
+
+// gestureShape returns the distance (in window pixels) and the angle between
+// the first two fingers.
+func gestureShape() (dist, angle float32) {
+	ax, ay := Touches[0].windowPosition()
+	bx, by := Touches[1].windowPosition()
+	dx, dy := float64(bx-ax), float64(by-ay)
+	return float32(math.Hypot(dx, dy)), float32(math.Atan2(dy, dx))
+}
+
+func updateGesture() {
+	if !gesture.active {
+		return
+	}
+	d, a := gestureShape()
+	ax, ay := Touches[0].windowPosition()
+	bx, by := Touches[1].windowPosition()
+	x, y := WindowToScreen((ax+bx)/2, (ay+by)/2)
+
+	if !gesture.pinching && gesture.dist0 > 0 {
+		s := d/gesture.dist0 - 1
+		gesture.pinching = s > pinchThreshold || s < -pinchThreshold
+	}
+	if gesture.pinching && d != gesture.dist && gesture.dist > 0 {
+		Loop.Pinch(d/gesture.dist, x, y)
+		gesture.dist = d
+	}
+
+	if !gesture.rotating {
+		r := wrapAngle(a - gesture.angle0)
+		gesture.rotating = r > rotateThreshold || r < -rotateThreshold
+	}
+	if gesture.rotating && a != gesture.angle {
+		Loop.Rotate(wrapAngle(a-gesture.angle), x, y)
+		gesture.angle = a
+	}
+}
+
+// wrapAngle returns the same angle, between -π and π.
+func wrapAngle(a float32) float32 {
+	for a > math.Pi {
+		a -= 2 * math.Pi
+	}
+	for a < -math.Pi {
+		a += 2 * math.Pi
+	}
+	return a
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,54 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package internal
+
+//------------------------------------------------------------------------------
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+//------------------------------------------------------------------------------
+
+// UserDataDir returns the directory where the game can write its data, and
+// creates it if needed. It's named after the title of the game, inside the
+// standard location of the platform (following the XDG rules on Linux and
+// other unix systems).
+func UserDataDir() (string, error) {
+	if Config.Title == "" {
+		return "", errors.New("no title to name the user data directory")
+	}
+	d, err := userDataHome()
+	if err != nil {
+		return "", err
+	}
+	d = filepath.Join(d, Config.Title)
+	err = os.MkdirAll(d, 0755)
+	if err != nil {
+		return "", err
+	}
+	return d, nil
+}
+
+func userDataHome() (string, error) {
+	switch runtime.GOOS {
+	case "windows", "darwin", "ios", "plan9":
+		return os.UserConfigDir()
+	}
+
+	// Relative paths are ignored, as required by the XDG specification
+	if d := os.Getenv("XDG_DATA_HOME"); filepath.IsAbs(d) {
+		return d, nil
+	}
+	h, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(h, ".local", "share"), nil
+}
+
+//------------------------------------------------------------------------------
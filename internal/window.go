@@ -61,6 +61,9 @@ func OpenWindow(
 		}
 	}
 	fl := C.SDL_WINDOW_OPENGL | C.SDL_WINDOW_RESIZABLE | C.Uint32(fs)
+	if Offscreen {
+		fl |= C.SDL_WINDOW_HIDDEN
+	}
 
 	Window.window = C.SDL_CreateWindow(
 		t,
@@ -93,6 +96,9 @@ func OpenWindow(
 	return nil
 }
 
+// OpenGLInfo describes the OpenGL context, once the window is opened.
+var OpenGLInfo string
+
 // logOpenGLInfos displays information about the OpenGL context
 func logOpenGLInfos() {
 	s := "OpenGL: "
@@ -128,6 +134,7 @@ func logOpenGLInfos() {
 			s += ", NO vsync"
 		}
 	}
+	OpenGLInfo = s
 	Debug.Println(s)
 }
 
@@ -149,6 +156,8 @@ func SwapWindow() {
 
 //------------------------------------------------------------------------------
 
+// SetFullscreen changes the fullscreen state of the window, and saves it in the
+// per-user settings file.
 func SetFullscreen(f bool) {
 	var fs C.Uint32
 	if f {
@@ -159,6 +168,12 @@ func SetFullscreen(f bool) {
 		}
 	}
 	C.SDL_SetWindowFullscreen(Window.window, fs)
+
+	Config.Fullscreen = f
+	err := SaveSettings()
+	if err != nil {
+		Debug.Printf("unable to save settings: %s", err)
+	}
 }
 
 func GetFullscreen() bool {
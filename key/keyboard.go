@@ -17,6 +17,27 @@ func IsPressed(pos Position) bool {
 	return internal.KeyState[pos]
 }
 
+// JustPressed returns true if the key at position pos has been pressed since
+// the previous Update step. Meant to be called during the Update callback; a
+// key pressed and released between two steps is still reported.
+func JustPressed(pos Position) bool {
+	return int(pos) < len(internal.KeyJustPressed) && internal.KeyJustPressed[pos]
+}
+
+// JustReleased returns true if the key at position pos has been released
+// since the previous Update step. Meant to be called during the Update
+// callback.
+func JustReleased(pos Position) bool {
+	return int(pos) < len(internal.KeyJustReleased) && internal.KeyJustReleased[pos]
+}
+
+// PressDuration returns the time (in seconds) since the key at position pos
+// was pressed, counted in Update steps. On the step where the key is released,
+// it returns the total time the key was held. Otherwise it returns 0.
+func PressDuration(pos Position) float64 {
+	return internal.KeyDuration(pos)
+}
+
 // LabelOf returns the key label at the specified position in the current
 // layout.
 func LabelOf(pos Position) Label {
@@ -0,0 +1,109 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package carol_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/drakmaniso/carol"
+	"github.com/drakmaniso/carol/key"
+	"github.com/drakmaniso/carol/mouse"
+)
+
+//------------------------------------------------------------------------------
+
+type edgeLoop struct {
+	carol.Handlers
+	pressed, released int
+	held              float64
+}
+
+func (l *edgeLoop) Setup() error { return nil }
+
+func (l *edgeLoop) Update() error {
+	if key.JustPressed(key.PositionSpace) {
+		l.pressed++
+	}
+	if key.JustReleased(key.PositionSpace) {
+		l.released++
+		l.held = key.PressDuration(key.PositionSpace)
+	}
+	return nil
+}
+
+func (l *edgeLoop) Draw(_, _ float64) error { return nil }
+
+//------------------------------------------------------------------------------
+
+func TestJustPressed(t *testing.T) {
+	ts := carol.TimeStep()
+	script := carol.Script{
+		// A tap between two steps
+		{Kind: carol.EventKeyDown, Time: 10.2 * ts, Label: key.LabelSpace, Position: key.PositionSpace},
+		{Kind: carol.EventKeyUp, Time: 10.6 * ts, Label: key.LabelSpace, Position: key.PositionSpace},
+		// A long press
+		{Kind: carol.EventKeyDown, Time: 20.5 * ts, Label: key.LabelSpace, Position: key.PositionSpace},
+		{Kind: carol.EventKeyUp, Time: 32.5 * ts, Label: key.LabelSpace, Position: key.PositionSpace},
+	}
+
+	var l edgeLoop
+	s, err := carol.Simulate(&l, &script, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	err = s.Step(15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.pressed != 1 || l.released != 1 {
+		t.Errorf("tap reported as %d presses and %d releases, expected 1 of each", l.pressed, l.released)
+	}
+	if l.held != 0 {
+		t.Errorf("tap held for %v, expected 0", l.held)
+	}
+
+	err = s.Step(45)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.pressed != 2 || l.released != 2 {
+		t.Errorf("got %d presses and %d releases, expected 2 of each", l.pressed, l.released)
+	}
+	if math.Abs(l.held-12*ts) > ts/2 {
+		t.Errorf("key held for %v, expected %v", l.held, 12*ts)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestInvalidButtons(t *testing.T) {
+	script := carol.Script{
+		{Kind: carol.EventMouseButtonDown, Time: 0, Button: 0},
+		{Kind: carol.EventMouseButtonDown, Time: 0, Button: 200},
+		{Kind: carol.EventKeyDown, Time: 0, Position: 1000},
+	}
+
+	var l edgeLoop
+	s, err := carol.Simulate(&l, &script, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	err = s.Step(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mouse.PressDuration(0) != 0 || mouse.PressDuration(200) != 0 || key.PressDuration(1000) != 0 {
+		t.Errorf("non-zero duration for invalid buttons")
+	}
+	if key.JustPressed(1000) {
+		t.Errorf("invalid key reported as pressed")
+	}
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,76 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package carol
+
+//------------------------------------------------------------------------------
+
+import (
+	"github.com/drakmaniso/carol/internal"
+)
+
+//------------------------------------------------------------------------------
+
+// A LogLevel is the severity of a log message.
+type LogLevel = internal.LogLevel
+
+// The log levels, by increasing severity.
+const (
+	DebugLevel   = internal.DebugLevel
+	InfoLevel    = internal.InfoLevel
+	WarningLevel = internal.WarningLevel
+	ErrorLevel   = internal.ErrorLevel
+)
+
+// A LogEntry is a message kept in the log history.
+type LogEntry = internal.LogEntry
+
+// The loggers for the game, in the "user" subsystem.
+var (
+	userDebug   = internal.Logger{System: "user", Level: DebugLevel}
+	userInfo    = internal.Logger{System: "user", Level: InfoLevel}
+	userWarning = internal.Logger{System: "user", Level: WarningLevel}
+	userError   = internal.Logger{System: "user", Level: ErrorLevel}
+)
+
+//------------------------------------------------------------------------------
+
+// Log logs a formated message.
+func Log(format string, v ...interface{}) {
+	userInfo.Printf(format, v...)
+}
+
+// LogDebug logs a formated message, at debug level.
+func LogDebug(format string, v ...interface{}) {
+	userDebug.Printf(format, v...)
+}
+
+// LogWarning logs a formated message, at warning level.
+func LogWarning(format string, v ...interface{}) {
+	userWarning.Printf(format, v...)
+}
+
+// LogError logs a formated message, at error level.
+func LogError(format string, v ...interface{}) {
+	userError.Printf(format, v...)
+}
+
+//------------------------------------------------------------------------------
+
+// SetLogLevel changes the minimum level of the messages logged for a
+// subsystem ("carol", "pixel", "gl", "poly", "user"...), or for all subsystems
+// without their own level if system is empty. Unless changed before Run, the
+// default is DebugLevel if the Debug option is set, InfoLevel otherwise.
+//
+// Messages below the minimum level are neither written nor kept in the log
+// history.
+func SetLogLevel(system string, l LogLevel) {
+	internal.SetLogLevel(system, l)
+}
+
+// RecentLog returns the last messages logged (up to 256), from the oldest.
+func RecentLog() []LogEntry {
+	return internal.RecentLog()
+}
+
+//------------------------------------------------------------------------------
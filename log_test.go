@@ -0,0 +1,55 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package carol_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/drakmaniso/carol"
+	"github.com/drakmaniso/carol/internal"
+)
+
+//------------------------------------------------------------------------------
+
+func TestLog(t *testing.T) {
+	var out bytes.Buffer
+	o := internal.LogOutput
+	internal.LogOutput = &out
+	restore := internal.SaveLogLevels()
+	defer func() {
+		internal.LogOutput = o
+		restore()
+	}()
+
+	carol.SetLogLevel("", carol.WarningLevel)
+	carol.SetLogLevel("user", carol.DebugLevel)
+
+	carol.LogDebug("one %d", 1)
+	internal.Log.Printf("filtered")
+	internal.Warning.Printf("two")
+	carol.LogError("three\n")
+
+	s := out.String()
+	if strings.Contains(s, "filtered") {
+		t.Errorf("message below level written: %q", s)
+	}
+	for _, m := range []string{"DEBUG user: one 1", "WARNING carol: two", "ERROR user: three\n"} {
+		if !strings.Contains(s, m) {
+			t.Errorf("missing %q in %q", m, s)
+		}
+	}
+
+	h := carol.RecentLog()
+	if len(h) < 3 {
+		t.Fatalf("history too short: %v", h)
+	}
+	h = h[len(h)-3:]
+	if h[0].Message != "one 1" || h[1].System != "carol" || h[2].Level != carol.ErrorLevel {
+		t.Errorf("unexpected history: %+v", h)
+	}
+}
+
+//------------------------------------------------------------------------------
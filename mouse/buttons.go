@@ -29,4 +29,26 @@ func IsPressed(b Button) bool {
 	return internal.MouseButtons&m != 0
 }
 
+// JustPressed returns true if a specific button has been pressed since the
+// previous Update step. Meant to be called during the Update callback; a
+// click between two steps is still reported.
+func JustPressed(b Button) bool {
+	var m uint32 = 1 << (b - 1)
+	return internal.MouseJustPressed&m != 0
+}
+
+// JustReleased returns true if a specific button has been released since the
+// previous Update step. Meant to be called during the Update callback.
+func JustReleased(b Button) bool {
+	var m uint32 = 1 << (b - 1)
+	return internal.MouseJustReleased&m != 0
+}
+
+// PressDuration returns the time (in seconds) since a specific button was
+// pressed, counted in Update steps. On the step where the button is released,
+// it returns the total time the button was held. Otherwise it returns 0.
+func PressDuration(b Button) float64 {
+	return internal.MouseDuration(b)
+}
+
 //------------------------------------------------------------------------------
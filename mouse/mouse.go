@@ -25,6 +25,12 @@ func Delta() (dx, dy int32) {
 	return dx, dy
 }
 
+// Wheel returns the wheel motion since the previous Update step. It is meant
+// to be called during the Update callback.
+func Wheel() (dx, dy int32) {
+	return internal.MouseWheelX, internal.MouseWheelY
+}
+
 // SetRelativeMode enables or disables the relative mode, where the mouse is
 // hidden and mouse motions are continuously reported.
 func SetRelativeMode(enabled bool) error {
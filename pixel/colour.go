@@ -6,7 +6,7 @@ package pixel
 //------------------------------------------------------------------------------
 
 import (
-	"errors"
+	"fmt"
 
 	"github.com/drakmaniso/carol/colour"
 	"github.com/drakmaniso/carol/x/gl"
@@ -67,7 +67,7 @@ func ColorCount() int {
 // Note: The palette contains a maximum of 256 colors.
 func NewColor(name string, v colour.Colour) Color {
 	if palette.count > 255 {
-		setErr("in NewColor", errors.New("impossible to add color \""+name+"\": maximum color count reached."))
+		setErr("in NewColor", fmt.Errorf("%w: impossible to add color %q", ErrPaletteFull, name))
 		return Color(0)
 	}
 
@@ -80,7 +80,7 @@ func NewColor(name string, v colour.Colour) Color {
 
 	if name != "" {
 		if _, ok := palette.names[name]; ok {
-			setErr("in NewColor", errors.New(`impossible to add color: name "`+name+`" already taken.`))
+			setErr("in NewColor", fmt.Errorf("%w: %q", ErrColorNameTaken, name))
 			return Color(0)
 		}
 		palette.names[name] = c
@@ -93,7 +93,7 @@ func NewColor(name string, v colour.Colour) Color {
 
 func requestColor(v colour.Colour) Color {
 	if palette.count > 255 {
-		setErr("in requestColor", errors.New("impossible to automatically add color: maximum color count reached."))
+		setErr("in requestColor", fmt.Errorf("%w: impossible to automatically add color", ErrPaletteFull))
 		return Color(0)
 	}
 
@@ -126,7 +126,7 @@ func requestColor(v colour.Colour) Color {
 func GetColor(name string) Color {
 	c, ok := palette.names[name]
 	if !ok {
-		setErr("in GetColor", errors.New("color \""+name+"\" not found"))
+		setErr("in GetColor", fmt.Errorf("%w: %q", ErrColorNotFound, name))
 	}
 	return c
 }
@@ -0,0 +1,140 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package pixel
+
+//------------------------------------------------------------------------------
+
+import (
+	"image"
+	"image/draw"
+
+	"github.com/drakmaniso/carol/internal"
+)
+
+//------------------------------------------------------------------------------
+
+var cursor = struct {
+	picture  *Picture
+	hotspot  Coord
+	software bool
+	visible  bool
+}{
+	visible: true,
+}
+
+//------------------------------------------------------------------------------
+
+// ShowCursor shows or hides the mouse cursor, whether it's the system cursor,
+// a hardware cursor or a software one.
+func ShowCursor(visible bool) {
+	cursor.visible = visible
+	if !cursor.software || cursor.picture == nil {
+		internal.CursorShow(visible)
+	}
+}
+
+// SetCursor replaces the system cursor with a picture. The hotspot is the
+// position in the picture that corresponds to the mouse position.
+//
+// The cursor is handled by the system, but scaled to the current pixel size
+// (and updated each time it changes).
+func SetCursor(p *Picture, hotspot Coord) error {
+	cursor.picture, cursor.hotspot = p, hotspot
+	cursor.software = false
+	err := applyCursor()
+	if err != nil {
+		return err
+	}
+	internal.CursorShow(cursor.visible)
+	return nil
+}
+
+// SetSoftwareCursor hides the system cursor, and paints a picture at the mouse
+// position instead, on top of everything painted on the virtual screen. Unlike
+// SetCursor, the cursor is aligned with the pixels of the screen, but follows
+// the mouse with the latency of a frame.
+//
+// The cursor is not painted when the mouse is outside the window, or in
+// relative mode.
+func SetSoftwareCursor(p *Picture, hotspot Coord) {
+	cursor.picture, cursor.hotspot = p, hotspot
+	cursor.software = true
+	internal.CursorReset()
+	internal.CursorShow(false)
+}
+
+// ResetCursor restores the system cursor.
+func ResetCursor() {
+	cursor.picture = nil
+	cursor.software = false
+	internal.CursorReset()
+	internal.CursorShow(cursor.visible)
+}
+
+//------------------------------------------------------------------------------
+
+// applyCursor creates the hardware cursor at the current pixel size.
+func applyCursor() error {
+	if internal.Headless || cursor.picture == nil {
+		return nil
+	}
+
+	m, err := cursor.picture.decode()
+	if err != nil {
+		return internal.Error("in cursor picture", err)
+	}
+
+	// Scale the picture with nearest neighbour filtering
+	s := int(screen.pixel)
+	if s < 1 {
+		s = 1
+	}
+	b := m.Bounds()
+	sm := image.NewNRGBA(image.Rect(0, 0, b.Dx()*s, b.Dy()*s))
+	for y := 0; y < b.Dy()*s; y++ {
+		for x := 0; x < b.Dx()*s; x++ {
+			sm.Set(x, y, m.At(b.Min.X+x/s, b.Min.Y+y/s))
+		}
+	}
+
+	return internal.CursorSetImage(
+		sm,
+		int32(cursor.hotspot.X)*int32(s),
+		int32(cursor.hotspot.Y)*int32(s),
+	)
+}
+
+// paintCursor adds the software cursor to the stamps.
+func paintCursor() {
+	if !cursor.software || !cursor.visible || cursor.picture == nil {
+		return
+	}
+	if !internal.HasMouseFocus || internal.MouseGetRelativeMode() {
+		return
+	}
+	p := Mouse().Minus(cursor.hotspot)
+	cursor.picture.Paint(p.X, p.Y)
+}
+
+//------------------------------------------------------------------------------
+
+// decode reads the image file of the picture. Indexed pictures use the
+// colors of the file's own palette.
+func (p *Picture) decode() (*image.NRGBA, error) {
+	f, err := internal.Assets.Open(p.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	b := img.Bounds()
+	m := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(m, m.Bounds(), img, b.Min, draw.Src)
+	return m, nil
+}
+
+//------------------------------------------------------------------------------
@@ -15,6 +15,18 @@ func init() {
 }
 
 func drawHook() error {
+	if internal.Headless {
+		stamps = stamps[:0]
+		return nil
+	}
+
+	if internal.Config.Debug {
+		reloadIfChanged()
+	}
+
+	paintLogOverlay()
+	paintCursor()
+
 	if palette.changed {
 		paletteSSBO.SubData(colours[:], 0)
 		palette.changed = false
@@ -39,6 +51,8 @@ func drawHook() error {
 		}
 	}
 
+	captureGIF()
+
 	blitScreen()
 
 	return nil
@@ -6,26 +6,43 @@ package pixel
 //------------------------------------------------------------------------------
 
 import (
+	"errors"
+
 	"github.com/drakmaniso/carol/internal"
 )
 
 //------------------------------------------------------------------------------
 
-var stickyErr error
+// The loggers of the pixel subsystem.
+var (
+	debug   = internal.Logger{System: "pixel", Level: internal.DebugLevel}
+	warning = internal.Logger{System: "pixel", Level: internal.WarningLevel}
+)
+
+//------------------------------------------------------------------------------
+
+// Errors reported by package pixel. They can be tested with errors.Is.
+var (
+	ErrPictureNotFound = errors.New("picture not found")
+	ErrColorNotFound   = errors.New("color not found")
+	ErrPaletteFull     = errors.New("maximum color count reached")
+	ErrColorNameTaken  = errors.New("color name already taken")
+)
+
+//------------------------------------------------------------------------------
+
+var stickyErr internal.StickyError
 
 // Err returns the first unchecked error of package pixel, and considers it
-// checked.
+// checked. If Config.CollectErrors is set, it returns all unchecked errors
+// instead.
 func Err() error {
-	err := stickyErr
-	stickyErr = nil
-	return err
+	return stickyErr.Err()
 }
 
 func setErr(context string, err error) {
-	if stickyErr == nil {
-		stickyErr = internal.Error(context, err)
-	}
-	internal.Debug.Printf("pixel error: %s", internal.Error(context, err))
+	stickyErr.Set(internal.Error(context, err))
+	warning.Printf("%s", internal.Error(context, err))
 }
 
 //------------------------------------------------------------------------------
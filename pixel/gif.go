@@ -0,0 +1,277 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package pixel
+
+//------------------------------------------------------------------------------
+
+import (
+	"bufio"
+	"compress/lzw"
+	"errors"
+	"image"
+	"image/color"
+	"io"
+
+	"github.com/drakmaniso/carol/internal"
+	"github.com/drakmaniso/carol/x/gl"
+)
+
+//------------------------------------------------------------------------------
+
+// gifFrame is a captured frame, waiting to be converted.
+type gifFrame struct {
+	pixels []uint8
+	w, h   int
+	pal    color.Palette
+	delay  int
+}
+
+var recorder struct {
+	active  bool
+	frames  chan gifFrame
+	done    chan error
+	last    float64 // Time of the last captured frame
+	elapsed float64 // Time not yet accounted for in the delays
+
+	// The pixels are read asynchronously, alternating between two buffers: a
+	// frame is retrieved during the capture of the next one.
+	buffers [2]gl.PixelBuffer
+	current int
+	pending *gifFrame // Frame transfered in the other buffer, without pixels
+}
+
+// gifBacklog is the number of captured frames waiting for conversion; when
+// the encoder can't keep up, frames are skipped.
+const gifBacklog = 16
+
+//------------------------------------------------------------------------------
+
+// StartGIF starts recording the virtual screen as an animated GIF, written to
+// w as the recording progresses.
+//
+// A frame is captured after each Draw, with the current palette. The delays
+// between frames follow the game time, rounded to the 1/100s resolution of
+// the format: at 60 frames per second, only one frame out of two (or three)
+// is kept. The conversion runs in a separate goroutine.
+func StartGIF(w io.Writer) error {
+	if recorder.active {
+		return errors.New("GIF recording already started")
+	}
+	if internal.Headless || internal.Config.ScreenMode == "direct" {
+		return errors.New("no virtual screen to record")
+	}
+
+	recorder.active = true
+	recorder.frames = make(chan gifFrame, gifBacklog)
+	recorder.done = make(chan error)
+	recorder.last = internal.VisibleNow
+	recorder.elapsed = 0
+	recorder.pending = nil
+
+	go encodeGIF(w, recorder.frames, recorder.done)
+	return nil
+}
+
+// StopGIF stops the recording, and waits until the remaining frames are
+// written.
+func StopGIF() error {
+	if !recorder.active {
+		return nil
+	}
+	recorder.active = false
+	if recorder.pending != nil {
+		f := *recorder.pending
+		recorder.pending = nil
+		f.pixels = make([]uint8, 4*f.w*f.h)
+		recorder.buffers[1-recorder.current].GetData(f.pixels)
+		recorder.frames <- f
+	}
+	for i := range recorder.buffers {
+		if recorder.buffers[i].Size() > 0 {
+			recorder.buffers[i].Delete()
+		}
+	}
+	close(recorder.frames)
+	return internal.Error("in GIF recording", <-recorder.done)
+}
+
+// IsRecordingGIF returns true if a GIF recording is in progress.
+func IsRecordingGIF() bool {
+	return recorder.active
+}
+
+//------------------------------------------------------------------------------
+
+// captureGIF is called after each Draw.
+func captureGIF() {
+	if !recorder.active {
+		return
+	}
+
+	now := internal.VisibleNow
+	recorder.elapsed += now - recorder.last
+	recorder.last = now
+	delay := int(recorder.elapsed * 100)
+	if delay < 2 {
+		// Most GIF viewers don't support shorter delays
+		return
+	}
+	recorder.elapsed -= float64(delay) / 100
+
+	// Start the transfer of this frame
+	w, h := int(screen.size.X), int(screen.size.Y)
+	b := &recorder.buffers[recorder.current]
+	if b.Size() < uintptr(4*w*h) {
+		if b.Size() > 0 {
+			b.Delete()
+		}
+		*b = gl.NewPixelBuffer(uintptr(4*w*h), gl.StaticStorage)
+	}
+	screen.buffer.ReadPixelsTo(gl.ColorAttachment0, 0, 0, int32(w), int32(h), *b)
+	f := &gifFrame{
+		w:     w,
+		h:     h,
+		pal:   currentPalette(),
+		delay: delay,
+	}
+	recorder.current = 1 - recorder.current
+
+	// Retrieve the previous one
+	p := recorder.pending
+	recorder.pending = f
+	if p == nil {
+		return
+	}
+	if len(recorder.frames) == cap(recorder.frames) {
+		// The encoder is late: skip the previous frame, this one gets its delay
+		f.delay += p.delay
+		return
+	}
+	p.pixels = make([]uint8, 4*p.w*p.h)
+	recorder.buffers[recorder.current].GetData(p.pixels)
+	recorder.frames <- *p
+}
+
+// encodeGIF converts and writes the frames as they arrive, and finishes the
+// GIF when the channel is closed.
+func encodeGIF(w io.Writer, frames <-chan gifFrame, done chan<- error) {
+	var g gifWriter
+	g.w = bufio.NewWriter(w)
+	var r image.Rectangle
+	for f := range frames {
+		m := paletted(f.pixels, f.w, f.h, f.pal)
+		if r.Empty() {
+			r = m.Bounds()
+			g.header(r.Dx(), r.Dy())
+		} else if m.Bounds() != r {
+			// The screen has been resized
+			m = resizePaletted(m, r)
+		}
+		g.frame(m, f.delay)
+	}
+	if r.Empty() {
+		done <- errors.New("no frame recorded")
+		return
+	}
+	done <- g.close()
+}
+
+// resizePaletted crops or extends m to bounds r.
+func resizePaletted(m *image.Paletted, r image.Rectangle) *image.Paletted {
+	n := image.NewPaletted(r, m.Palette)
+	for y := r.Min.Y; y < r.Max.Y && y < m.Rect.Max.Y; y++ {
+		copy(n.Pix[n.PixOffset(r.Min.X, y):n.PixOffset(r.Max.X, y)], m.Pix[m.PixOffset(0, y):m.PixOffset(m.Rect.Max.X, y)])
+	}
+	return n
+}
+
+//------------------------------------------------------------------------------
+
+// A gifWriter writes an animated GIF one frame at a time. The first error is
+// kept, and returned by close.
+type gifWriter struct {
+	w   *bufio.Writer
+	err error
+	buf [256]byte // For the data sub-blocks
+	n   int
+}
+
+func (g *gifWriter) write(p ...byte) {
+	if g.err == nil {
+		_, g.err = g.w.Write(p)
+	}
+}
+
+func (g *gifWriter) header(w, h int) {
+	g.write([]byte("GIF89a")...)
+	g.write(byte(w), byte(w>>8), byte(h), byte(h>>8), 0, 0, 0)
+	// Loop forever
+	g.write(0x21, 0xFF, 11)
+	g.write([]byte("NETSCAPE2.0")...)
+	g.write(3, 1, 0, 0, 0)
+}
+
+func (g *gifWriter) frame(m *image.Paletted, delay int) {
+	// Graphic control extension
+	g.write(0x21, 0xF9, 4, 0, byte(delay), byte(delay>>8), 0, 0)
+
+	// Image descriptor, with a local color table
+	bits := 1
+	for 1<<uint(bits) < len(m.Palette) {
+		bits++
+	}
+	b := m.Bounds()
+	g.write(0x2C, 0, 0, 0, 0, byte(b.Dx()), byte(b.Dx()>>8), byte(b.Dy()), byte(b.Dy()>>8), 0x80|byte(bits-1))
+	for i := 0; i < 1<<uint(bits); i++ {
+		var r, gr, bl uint32
+		if i < len(m.Palette) {
+			r, gr, bl, _ = m.Palette[i].RGBA()
+		}
+		g.write(byte(r>>8), byte(gr>>8), byte(bl>>8))
+	}
+
+	// Image data
+	lw := bits
+	if lw < 2 {
+		lw = 2
+	}
+	g.write(byte(lw))
+	z := lzw.NewWriter(g, lzw.LSB, lw)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		z.Write(m.Pix[m.PixOffset(b.Min.X, y):m.PixOffset(b.Max.X, y)])
+	}
+	z.Close()
+	g.flush()
+	g.write(0)
+}
+
+// Write implements io.Writer, cutting the data into sub-blocks.
+func (g *gifWriter) Write(p []byte) (int, error) {
+	for _, c := range p {
+		g.n++
+		g.buf[g.n] = c
+		if g.n == 255 {
+			g.flush()
+		}
+	}
+	return len(p), g.err
+}
+
+func (g *gifWriter) flush() {
+	if g.n > 0 {
+		g.buf[0] = byte(g.n)
+		g.write(g.buf[:g.n+1]...)
+		g.n = 0
+	}
+}
+
+func (g *gifWriter) close() error {
+	g.write(0x3B)
+	if g.err == nil {
+		g.err = g.w.Flush()
+	}
+	return g.err
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,67 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package pixel
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"testing"
+)
+
+//------------------------------------------------------------------------------
+
+func TestEncodeGIF(t *testing.T) {
+	pal := color.Palette{
+		color.RGBA{0, 0, 0, 0xFF},
+		color.RGBA{0xFF, 0, 0, 0xFF},
+		color.RGBA{0, 0xFF, 0, 0xFF},
+	}
+	const w, h = 300, 7 // Enough data for several sub-blocks
+	frames := make(chan gifFrame, 3)
+	for i := 0; i < 3; i++ {
+		f := gifFrame{pixels: make([]uint8, 4*w*h), w: w, h: h, pal: pal, delay: 2 + i}
+		for p := 0; p < w*h; p++ {
+			c := pal[(p/(i+1))%len(pal)].(color.RGBA)
+			f.pixels[4*p], f.pixels[4*p+1], f.pixels[4*p+2], f.pixels[4*p+3] = c.R, c.G, c.B, c.A
+		}
+		frames <- f
+	}
+	close(frames)
+
+	var b bytes.Buffer
+	done := make(chan error, 1)
+	encodeGIF(&b, frames, done)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := gif.DecodeAll(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Image) != 3 || g.LoopCount != 0 {
+		t.Fatalf("decoded %d frames with loop count %d", len(g.Image), g.LoopCount)
+	}
+	for i, m := range g.Image {
+		if g.Delay[i] != 2+i {
+			t.Errorf("frame %d: delay %d, expected %d", i, g.Delay[i], 2+i)
+		}
+		if m.Bounds() != image.Rect(0, 0, w, h) {
+			t.Fatalf("frame %d: bounds %v", i, m.Bounds())
+		}
+		// The rows are stored from bottom to top
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				p := (h-1-y)*w + x
+				if m.At(x, y) != pal[(p/(i+1))%len(pal)] {
+					t.Fatalf("frame %d: wrong pixel at %d, %d", i, x, y)
+				}
+			}
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
@@ -8,9 +8,10 @@ import (
 	"image"
 	"image/color"
 	_ "image/png" // Activate PNG support
-	"os"
+	"io/fs"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/drakmaniso/carol/colour"
 
@@ -33,24 +34,72 @@ var (
 
 //------------------------------------------------------------------------------
 
-var picturesPath string
-
-func init() {
-	picturesPath = filepath.Join(internal.FilePath, "graphics")
-}
+// picturesPath is the directory of the pictures in the asset file system.
+const picturesPath = "graphics"
 
 //------------------------------------------------------------------------------
 
 func loadAllPictures() error {
-	// Scan all pictures
-	err := filepath.Walk(picturesPath, scan)
-	switch {
-	case os.IsNotExist(err):
+	err := scanAllPictures()
+	if err != nil {
+		return err
+	}
+
+	if internal.Headless {
 		return nil
+	}
+
+	return packAllPictures()
+}
+
+// scanAllPictures walks the graphics directory, and registers every picture
+// found. Pictures that are no longer present keep their handle, but become
+// empty.
+func scanAllPictures() error {
+	var found []imgfile
+	err := fs.WalkDir(internal.Assets, picturesPath, func(path string, d fs.DirEntry, err error) error {
+		return scan(path, d, err, &found)
+	})
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
 	case err != nil:
 		return internal.Error("while scanning images", err)
 	}
+	found = append(found, builtinPictures...)
+
+	seen := make(map[string]bool, len(found))
+	paths := make(map[string]bool, len(found))
+	rgbaFiles = rgbaFiles[:0]
+	indexedFiles = indexedFiles[:0]
+	for _, f := range found {
+		seen[f.name] = true
+		paths[f.path] = true
+		newPicture(f.name, f.path, f.mode, f.w, f.h)
+		switch f.mode {
+		case FullColor:
+			rgbaFiles = append(rgbaFiles, f)
+		case Indexed:
+			indexedFiles = append(indexedFiles, f)
+		}
+	}
 
+	for n, p := range pictures {
+		if !seen[n] {
+			mappings[p.mapping] = mapping{}
+		}
+	}
+	for p := range decoded {
+		if !paths[p] {
+			delete(decoded, p)
+		}
+	}
+
+	return nil
+}
+
+// packAllPictures packs the pictures into atlases, and uploads them to the
+// GPU, replacing the previous textures.
+func packAllPictures() error {
 	// Pack them into atlases
 	indexedAtlas = atlas.New(1024, 1024)
 	rgbaAtlas = atlas.New(1024, 1024)
@@ -60,14 +109,14 @@ func loadAllPictures() error {
 
 	{
 		iu := indexedAtlas.Unused()
-		internal.Debug.Printf(
+		debug.Printf(
 			"Packed %d indexed images in %d bins: %d unused pixels (%d kb, %d Mb)\n",
 			len(indexedFiles),
 			indexedAtlas.BinCount(),
 			iu, iu/1024, iu/(1024*1024),
 		)
 		ru := rgbaAtlas.Unused()
-		internal.Debug.Printf(
+		debug.Printf(
 			"Packed %d RGBA images in %d bins: %d unused pixels (%d kb, %d Mb)\n",
 			len(rgbaFiles),
 			rgbaAtlas.BinCount(),
@@ -77,6 +126,7 @@ func loadAllPictures() error {
 
 	// Create the indexed texture atlas
 	w, h := indexedAtlas.BinSize()
+	indexedTexture.Delete()
 	indexedTexture = gl.NewTextureArray2D(1, gl.R8UI, int32(w), int32(h), int32(indexedAtlas.BinCount()))
 	for i := int16(0); i < indexedAtlas.BinCount(); i++ {
 		m := image.NewPaletted(image.Rectangle{
@@ -97,6 +147,7 @@ func loadAllPictures() error {
 
 	// Create the RGBA texture atlas
 	w, h = rgbaAtlas.BinSize()
+	rgbaTexture.Delete()
 	rgbaTexture = gl.NewTextureArray2D(1, gl.SRGBA8, int32(w), int32(h), int32(rgbaAtlas.BinCount()))
 	for i := int16(0); i < rgbaAtlas.BinCount(); i++ {
 		m := image.NewNRGBA(image.Rectangle{
@@ -113,19 +164,34 @@ func loadAllPictures() error {
 	}
 	rgbaTexture.Bind(2)
 
-	internal.Debug.Printf("Loaded %d pictures.", len(pictures))
+	debug.Printf("Loaded %d pictures.", len(pictures))
 
 	return nil
 }
 
+// uploadMappings creates the buffer texture holding the mapping of each
+// picture into the atlases.
+func uploadMappings() {
+	mappingsTBO.Delete()
+	mappingsTBO = gl.NewBufferTexture(mappings, gl.R16I, gl.StaticStorage)
+	mappingsTBO.Bind(5)
+}
+
 //------------------------------------------------------------------------------
 
-func scan(path string, info os.FileInfo, err error) error {
+func scan(path string, d fs.DirEntry, err error, found *[]imgfile) error {
+	if err != nil {
+		return err
+	}
+	if d.IsDir() {
+		return nil
+	}
+	info, err := d.Info()
 	if err != nil {
 		return err
 	}
 
-	f, err := os.Open(path)
+	f, err := internal.Assets.Open(path)
 	if err != nil {
 		return internal.Error(`while opening image "`+path+`"`, err)
 	}
@@ -140,12 +206,8 @@ func scan(path string, info os.FileInfo, err error) error {
 		return internal.Error("decoding picture file", err)
 	}
 
-	fp, err := filepath.Rel(picturesPath, path)
-	if err != nil {
-		return err
-	}
+	fp := strings.TrimPrefix(path, picturesPath+"/")
 	n := strings.TrimSuffix(fp, filepath.Ext(fp))
-	n = filepath.ToSlash(n)
 	//TODO: check for width and height overflow
 	w, h := int16(conf.Width), int16(conf.Height)
 
@@ -153,8 +215,10 @@ func scan(path string, info os.FileInfo, err error) error {
 
 	case color.RGBAModel, color.NRGBAModel, color.GrayModel,
 		color.Gray16Model, color.RGBA64Model, color.NRGBA64Model:
-		newPicture(n, FullColor, w, h)
-		rgbaFiles = append(rgbaFiles, imgfile{name: n, path: path})
+		*found = append(*found, imgfile{
+			name: n, path: path, mode: FullColor, w: w, h: h,
+			modTime: info.ModTime(), size: info.Size(),
+		})
 
 	case color.AlphaModel, color.Alpha16Model:
 		return errors.New(`image "` + path + `" color model (16-bit alpha) not yet supported.`)
@@ -162,8 +226,10 @@ func scan(path string, info os.FileInfo, err error) error {
 	default:
 		_, ok := conf.ColorModel.(color.Palette)
 		if ok {
-			newPicture(n, Indexed, w, h)
-			indexedFiles = append(indexedFiles, imgfile{name: n, path: path})
+			*found = append(*found, imgfile{
+				name: n, path: path, mode: Indexed, w: w, h: h,
+				modTime: info.ModTime(), size: info.Size(),
+			})
 
 		} else {
 			return errors.New(`image "` + path + `" color model not recognized.`)
@@ -176,8 +242,14 @@ func scan(path string, info os.FileInfo, err error) error {
 //------------------------------------------------------------------------------
 
 type imgfile struct {
-	name string
-	path string
+	name    string
+	path    string
+	mode    Mode
+	w, h    int16
+	modTime time.Time
+	size    int64
+	// img is only used for the built-in pictures, which have no file
+	img image.Image
 }
 
 func (im imgfile) Size() (width, height int16) {
@@ -193,12 +265,7 @@ func (im imgfile) Paint(dest interface{}) error {
 	p := pictures[im.name]
 	_, px, py, pw, ph := p.getMap()
 
-	pf, err := os.Open(im.path)
-	if err != nil {
-		return err
-	}
-	defer pf.Close()
-	pm, _, err := image.Decode(pf)
+	pm, err := im.decode()
 	if err != nil {
 		return err
 	}
@@ -246,3 +313,41 @@ func (im imgfile) Paint(dest interface{}) error {
 }
 
 //------------------------------------------------------------------------------
+
+// decoded caches the decoded image files in debug mode, so that only the
+// modified files are decoded again when reloading.
+var decoded = map[string]decodedFile{}
+
+type decodedFile struct {
+	modTime time.Time
+	size    int64
+	img     image.Image
+}
+
+func (im imgfile) decode() (image.Image, error) {
+	if im.img != nil {
+		return im.img, nil
+	}
+
+	d, ok := decoded[im.path]
+	if ok && d.modTime.Equal(im.modTime) && d.size == im.size {
+		return d.img, nil
+	}
+
+	pf, err := internal.Assets.Open(im.path)
+	if err != nil {
+		return nil, err
+	}
+	defer pf.Close()
+	pm, _, err := image.Decode(pf)
+	if err != nil {
+		return nil, err
+	}
+
+	if internal.Config.Debug {
+		decoded[im.path] = decodedFile{modTime: im.modTime, size: im.size, img: pm}
+	}
+	return pm, nil
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,208 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package pixel
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drakmaniso/carol/internal"
+)
+
+//------------------------------------------------------------------------------
+
+// builtinPictures are the pictures created by the package itself, packed along
+// with the pictures of the graphics directory.
+var builtinPictures []imgfile
+
+var logOverlay struct {
+	lines int
+	level internal.LogLevel
+}
+
+// logOverlayDuration is how long a message stays in the overlay, in seconds.
+const logOverlayDuration = 10
+
+//------------------------------------------------------------------------------
+
+// ShowLog displays the last messages of the log on top of the screen, using a
+// small built-in font. Only the messages at or above a level are shown, and
+// each one disappears after 10 seconds.
+func ShowLog(lines int, level internal.LogLevel) {
+	logOverlay.lines = lines
+	logOverlay.level = level
+}
+
+// HideLog removes the log overlay.
+func HideLog() {
+	logOverlay.lines = 0
+}
+
+// paintLogOverlay adds the log overlay to the stamps.
+func paintLogOverlay() {
+	if logOverlay.lines <= 0 {
+		return
+	}
+
+	var l []internal.LogEntry
+	t := time.Now().Add(-logOverlayDuration * time.Second)
+	for _, e := range internal.RecentLog() {
+		if e.Level >= logOverlay.level && e.Time.After(t) {
+			l = append(l, e)
+		}
+	}
+	if len(l) > logOverlay.lines {
+		l = l[len(l)-logOverlay.lines:]
+	}
+
+	for i, e := range l {
+		m := e.System + ": " + e.Message
+		if j := strings.IndexByte(m, '\n'); j >= 0 {
+			m = m[:j]
+		}
+		x, y := int16(1), int16(1+i*logGlyphHeight)
+		for _, r := range strings.ToUpper(m) {
+			if x+logGlyphWidth > screen.size.X {
+				break
+			}
+			logGlyph(e.Level, r).Paint(x, y)
+			x += logGlyphWidth
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// Size of the cells of the built-in font (including the spacing).
+const (
+	logGlyphWidth  = 4
+	logGlyphHeight = 6
+)
+
+var logColors = [...]color.NRGBA{
+	internal.DebugLevel:   {160, 160, 160, 255},
+	internal.InfoLevel:    {255, 255, 255, 255},
+	internal.WarningLevel: {255, 220, 64, 255},
+	internal.ErrorLevel:   {255, 80, 80, 255},
+}
+
+var logGlyphs [len(logColors)][len(logFont)]*Picture
+
+func logGlyph(l internal.LogLevel, r rune) *Picture {
+	if int(l) >= len(logColors) {
+		l = internal.ErrorLevel
+	}
+	if r < ' ' || r >= ' '+rune(len(logFont)) {
+		r = '?'
+	}
+	g := &logGlyphs[l][r-' ']
+	if *g == nil {
+		*g = pictures[logGlyphName(int(l), int(r-' '))]
+	}
+	return *g
+}
+
+func logGlyphName(level, glyph int) string {
+	return "carol:log/" + strconv.Itoa(level) + "/" + strconv.Itoa(glyph)
+}
+
+func init() {
+	bg := color.NRGBA{0, 0, 0, 160}
+	for l, c := range logColors {
+		for g, f := range logFont {
+			m := image.NewNRGBA(image.Rect(0, 0, logGlyphWidth, logGlyphHeight))
+			draw.Draw(m, m.Bounds(), &image.Uniform{bg}, image.Point{}, draw.Src)
+			for y, row := range strings.Fields(f) {
+				for x, p := range row {
+					if p == '#' {
+						m.SetNRGBA(x, y, c)
+					}
+				}
+			}
+			builtinPictures = append(builtinPictures, imgfile{
+				name: logGlyphName(l, g),
+				mode: FullColor,
+				w:    logGlyphWidth,
+				h:    logGlyphHeight,
+				img:  m,
+			})
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// logFont is a 3x5 font for the characters from ' ' to '_'.
+var logFont = [...]string{
+	"... ... ... ... ...", // ' '
+	".#. .#. .#. ... .#.", // '!'
+	"#.# #.# ... ... ...", // '"'
+	"#.# ### #.# ### #.#", // '#'
+	".## ##. .#. .## ##.", // '$'
+	"#.. ..# .#. #.. ..#", // '%'
+	".#. #.# .#. #.# .##", // '&'
+	".#. .#. ... ... ...", // '\''
+	"..# .#. .#. .#. ..#", // '('
+	"#.. .#. .#. .#. #..", // ')'
+	"... #.# .#. #.# ...", // '*'
+	"... .#. ### .#. ...", // '+'
+	"... ... ... .#. #..", // ','
+	"... ... ### ... ...", // '-'
+	"... ... ... ... .#.", // '.'
+	"..# ..# .#. #.. #..", // '/'
+	"### #.# #.# #.# ###", // '0'
+	".#. ##. .#. .#. ###", // '1'
+	"##. ..# .#. #.. ###", // '2'
+	"##. ..# .#. ..# ##.", // '3'
+	"#.# #.# ### ..# ..#", // '4'
+	"### #.. ##. ..# ##.", // '5'
+	".## #.. ### #.# ###", // '6'
+	"### ..# .#. .#. .#.", // '7'
+	"### #.# ### #.# ###", // '8'
+	"### #.# ### ..# ##.", // '9'
+	"... .#. ... .#. ...", // ':'
+	"... .#. ... .#. #..", // ';'
+	"..# .#. #.. .#. ..#", // '<'
+	"... ### ... ### ...", // '='
+	"#.. .#. ..# .#. #..", // '>'
+	"##. ..# .#. ... .#.", // '?'
+	".#. #.# ### #.. .##", // '@'
+	".#. #.# ### #.# #.#", // 'A'
+	"##. #.# ##. #.# ##.", // 'B'
+	".## #.. #.. #.. .##", // 'C'
+	"##. #.# #.# #.# ##.", // 'D'
+	"### #.. ##. #.. ###", // 'E'
+	"### #.. ##. #.. #..", // 'F'
+	".## #.. #.# #.# .##", // 'G'
+	"#.# #.# ### #.# #.#", // 'H'
+	"### .#. .#. .#. ###", // 'I'
+	"..# ..# ..# #.# .#.", // 'J'
+	"#.# #.# ##. #.# #.#", // 'K'
+	"#.. #.. #.. #.. ###", // 'L'
+	"#.# ### ### #.# #.#", // 'M'
+	"##. #.# #.# #.# #.#", // 'N'
+	".#. #.# #.# #.# .#.", // 'O'
+	"##. #.# ##. #.. #..", // 'P'
+	".#. #.# #.# ##. .##", // 'Q'
+	"##. #.# ##. #.# #.#", // 'R'
+	".## #.. .#. ..# ##.", // 'S'
+	"### .#. .#. .#. .#.", // 'T'
+	"#.# #.# #.# #.# ###", // 'U'
+	"#.# #.# #.# #.# .#.", // 'V'
+	"#.# #.# ### ### #.#", // 'W'
+	"#.# #.# .#. #.# #.#", // 'X'
+	"#.# #.# .#. .#. .#.", // 'Y'
+	"### ..# .#. #.. ###", // 'Z'
+	"##. #.. #.. #.. ##.", // '['
+	"#.. #.. .#. ..# ..#", // '\\'
+	".## ..# ..# ..# .##", // ']'
+	".#. #.# ... ... ...", // '^'
+	"... ... ... ... ###", // '_'
+}
+
+//------------------------------------------------------------------------------
@@ -6,7 +6,7 @@ package pixel
 //------------------------------------------------------------------------------
 
 import (
-	"errors"
+	"fmt"
 )
 
 //------------------------------------------------------------------------------
@@ -14,6 +14,7 @@ import (
 type Picture struct {
 	mode    Mode
 	mapping uint16
+	path    string
 }
 
 var pictures map[string]*Picture
@@ -46,9 +47,20 @@ const (
 
 //------------------------------------------------------------------------------
 
-func newPicture(name string, mode Mode, w, h int16) *Picture {
+// newPicture registers a picture. If a picture with the same name already
+// exists (i.e. when reloading), it is updated in place, so that existing
+// handles stay valid.
+func newPicture(name string, path string, mode Mode, w, h int16) *Picture {
+	if p, ok := pictures[name]; ok {
+		p.mode = mode
+		p.path = path
+		mappings[p.mapping] = mapping{w: w, h: h}
+		return p
+	}
+
 	var p Picture
 	p.mode = mode
+	p.path = path
 	mappings = append(mappings, mapping{w: w, h: h})
 	p.mapping = uint16(len(mappings) - 1)
 	pictures[name] = &p
@@ -83,7 +95,7 @@ func (p *Picture) getMap() (binFlip int16, x, y, w, h int16) {
 func GetPicture(name string) *Picture {
 	p, ok := pictures[name]
 	if !ok {
-		setErr("in GetPicture", errors.New("picture \""+name+"\" not found"))
+		setErr("in GetPicture", fmt.Errorf("%w: %q", ErrPictureNotFound, name))
 	}
 	return p
 }
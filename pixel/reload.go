@@ -0,0 +1,120 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package pixel
+
+import (
+	"io/fs"
+	"time"
+
+	"github.com/drakmaniso/carol/internal"
+	"github.com/drakmaniso/carol/x/gl"
+)
+
+//------------------------------------------------------------------------------
+
+// watchInterval is the delay between two scans of the graphics directory.
+const watchInterval = 500 * time.Millisecond
+
+// watcher holds the result of the last scan of the graphics directory.
+var watcher struct {
+	stamps map[string]fileStamp
+	last   time.Time
+}
+
+//------------------------------------------------------------------------------
+
+// ReloadPictures scans the graphics directory again, and updates all added,
+// modified or removed pictures. Only the modified files are decoded again (in
+// debug mode), but the atlases are repacked and uploaded as a whole. Existing
+// *Picture values stay valid; removed pictures become empty.
+//
+// In debug mode, this is done automatically whenever a file changes.
+func ReloadPictures() error {
+	err := scanAllPictures()
+	if err != nil {
+		return err
+	}
+
+	if internal.Headless {
+		return nil
+	}
+
+	err = packAllPictures()
+	if err != nil {
+		return internal.Error("while reloading pictures", err)
+	}
+	uploadMappings()
+
+	if cursor.picture != nil && !cursor.software {
+		err := applyCursor()
+		if err != nil {
+			return err
+		}
+	}
+
+	return gl.Err()
+}
+
+// reloadIfChanged scans the graphics directory at regular intervals, and
+// reloads the pictures if its content differs from the previous scan. It is
+// called before each Draw, on the main thread, so that the scans cannot
+// overlap a change of the assets file system. Errors are only logged, to keep
+// the game running while files are being edited.
+func reloadIfChanged() {
+	if time.Since(watcher.last) < watchInterval {
+		return
+	}
+	watcher.last = time.Now()
+	s := stampPictures()
+	if watcher.stamps == nil || sameStamps(watcher.stamps, s) {
+		watcher.stamps = s
+		return
+	}
+	watcher.stamps = s
+
+	err := ReloadPictures()
+	if err != nil {
+		warning.Printf("Unable to reload pictures: %s", err)
+		return
+	}
+	debug.Printf("Reloaded pictures.")
+}
+
+//------------------------------------------------------------------------------
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func stampPictures() map[string]fileStamp {
+	s := map[string]fileStamp{}
+	fs.WalkDir(internal.Assets, picturesPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		s[path] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+	return s
+}
+
+func sameStamps(a, b map[string]fileStamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for p, sa := range a {
+		sb, ok := b[p]
+		if !ok || !sa.modTime.Equal(sb.modTime) || sa.size != sb.size {
+			return false
+		}
+	}
+	return true
+}
+
+//------------------------------------------------------------------------------
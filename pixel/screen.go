@@ -10,6 +10,7 @@ import (
 	"github.com/drakmaniso/carol/x/gl"
 	"github.com/drakmaniso/carol/internal"
 	"github.com/drakmaniso/carol/mouse"
+	"github.com/drakmaniso/carol/touch"
 )
 
 //------------------------------------------------------------------------------
@@ -63,6 +64,9 @@ func createScreen() {
 //------------------------------------------------------------------------------
 
 func createScreenTexture() {
+	if internal.Headless {
+		return
+	}
 	//TODO: delete previous texture
 	screen.texture = gl.NewTexture2D(1, gl.SRGB8, int32(screen.size.X), int32(screen.size.Y))
 	screen.buffer.Texture(gl.ColorAttachment0, screen.texture, 0)
@@ -73,6 +77,8 @@ func createScreenTexture() {
 //------------------------------------------------------------------------------
 
 func init() {
+	internal.WindowToScreen = windowToScreen
+
 	internal.ResizeScreen = func() {
 		switch internal.Config.ScreenMode {
 		case "Extend":
@@ -123,6 +129,13 @@ func init() {
 		screen.ox = (internal.Window.Width - w) / 2
 		screen.oy = (internal.Window.Height - h) / 2
 
+		if cursor.picture != nil && !cursor.software {
+			err := applyCursor()
+			if err != nil {
+				setErr("in cursor scaling", err)
+			}
+		}
+
 		internal.Loop.ScreenResized(screen.size.X, screen.size.Y, screen.pixel)
 	}
 }
@@ -130,7 +143,7 @@ func init() {
 //------------------------------------------------------------------------------
 
 func blitScreen() {
-	if internal.Config.ScreenMode == "direct" {
+	if internal.Config.ScreenMode == "direct" || internal.Offscreen {
 		return
 	}
 
@@ -153,11 +166,22 @@ func blitScreen() {
 
 // Mouse returns the mouse position on the virtual screen.
 func Mouse() Coord {
-	mx, my := mouse.Position()
-	return Coord{
-		X: int16((mx - screen.ox) / screen.pixel),
-		Y: int16((my - screen.oy) / screen.pixel),
+	x, y := windowToScreen(mouse.Position())
+	return Coord{X: x, Y: y}
+}
+
+// Touch returns the position of a finger on the virtual screen. It returns
+// false if the finger isn't touching the screen.
+func Touch(finger int64) (Coord, bool) {
+	t, ok := touch.Finger(finger)
+	return Coord{X: t.ScreenX, Y: t.ScreenY}, ok
+}
+
+func windowToScreen(x, y int32) (int16, int16) {
+	if screen.pixel < 1 {
+		return int16(x), int16(y)
 	}
+	return int16((x - screen.ox) / screen.pixel), int16((y - screen.oy) / screen.pixel)
 }
 
 //------------------------------------------------------------------------------
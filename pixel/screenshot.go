@@ -0,0 +1,96 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package pixel
+
+//------------------------------------------------------------------------------
+
+import (
+	"errors"
+	"image"
+	"image/color"
+
+	"github.com/drakmaniso/carol/colour"
+	"github.com/drakmaniso/carol/internal"
+	"github.com/drakmaniso/carol/x/gl"
+)
+
+//------------------------------------------------------------------------------
+
+// Screenshot returns a copy of the virtual screen, at native resolution and
+// with the current palette. Colors that are not in the palette (e.g. from full
+// color pictures) are replaced by the nearest palette entry.
+//
+// The content is the last screen drawn; it's only available after the first
+// frame.
+func Screenshot() (*image.Paletted, error) {
+	if internal.Headless || internal.Config.ScreenMode == "direct" {
+		return nil, errors.New("no virtual screen to capture")
+	}
+
+	w, h := int(screen.size.X), int(screen.size.Y)
+	buf := make([]uint8, 4*w*h)
+	screen.buffer.ReadPixels(gl.ColorAttachment0, 0, 0, int32(w), int32(h), buf)
+	err := gl.Err()
+	if err != nil {
+		return nil, internal.Error("in screenshot", err)
+	}
+
+	return paletted(buf, w, h, currentPalette()), nil
+}
+
+// currentPalette returns a copy of the palette, in sRGB color space.
+func currentPalette() color.Palette {
+	pal := make(color.Palette, palette.count)
+	for i := range pal {
+		c := colour.SRGBA8Of(colour.RGBA(colours[i]))
+		pal[i] = color.RGBA{c.R, c.G, c.B, c.A}
+	}
+	return pal
+}
+
+// paletted converts the content of the screen, as read by ReadPixels, to a
+// paletted image. If the palette is empty, the image is all black.
+func paletted(buf []uint8, w, h int, pal color.Palette) *image.Paletted {
+	if len(pal) == 0 {
+		pal = color.Palette{color.RGBA{0, 0, 0, 0xFF}}
+	}
+	m := image.NewPaletted(image.Rect(0, 0, w, h), pal)
+	cache := map[[3]uint8]uint8{}
+	for y := 0; y < h; y++ {
+		src := buf[4*w*(h-1-y):] // OpenGL rows are bottom to top
+		dst := m.Pix[m.Stride*y:]
+		for x := 0; x < w; x++ {
+			k := [3]uint8{src[4*x], src[4*x+1], src[4*x+2]}
+			i, ok := cache[k]
+			if !ok {
+				i = nearestColor(pal, k)
+				cache[k] = i
+			}
+			dst[x] = i
+		}
+	}
+	return m
+}
+
+// nearestColor returns the index of the opaque palette entry closest to c. The
+// palette must not be empty (paletted ensures it).
+func nearestColor(pal color.Palette, c [3]uint8) uint8 {
+	best, dist := 0, -1
+	for i, p := range pal {
+		p := p.(color.RGBA)
+		if p.A == 0 && len(pal) > 1 {
+			continue
+		}
+		dr := int(p.R) - int(c[0])
+		dg := int(p.G) - int(c[1])
+		db := int(p.B) - int(c[2])
+		d := dr*dr + dg*dg + db*db
+		if dist < 0 || d < dist {
+			best, dist = i, d
+		}
+	}
+	return uint8(best)
+}
+
+//------------------------------------------------------------------------------
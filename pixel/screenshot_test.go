@@ -0,0 +1,73 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package pixel
+
+import (
+	"image/color"
+	"testing"
+)
+
+//------------------------------------------------------------------------------
+
+func TestNearestColor(t *testing.T) {
+	pal := color.Palette{
+		color.RGBA{0, 0, 0, 0}, // transparent, never chosen
+		color.RGBA{0, 0, 0, 0xFF},
+		color.RGBA{0xFF, 0, 0, 0xFF},
+		color.RGBA{0xFF, 0xFF, 0xFF, 0xFF},
+	}
+	tests := []struct {
+		in  [3]uint8
+		out uint8
+	}{
+		{[3]uint8{0, 0, 0}, 1},
+		{[3]uint8{10, 20, 0}, 1},
+		{[3]uint8{200, 30, 30}, 2},
+		{[3]uint8{250, 240, 230}, 3},
+	}
+	for _, tt := range tests {
+		if c := nearestColor(pal, tt.in); c != tt.out {
+			t.Errorf("nearestColor(%v) = %d, expected %d", tt.in, c, tt.out)
+		}
+	}
+
+	// A palette with only a transparent entry
+	if c := nearestColor(pal[:1], [3]uint8{1, 2, 3}); c != 0 {
+		t.Errorf("nearestColor with single entry = %d, expected 0", c)
+	}
+}
+
+func TestPaletted(t *testing.T) {
+	// Two rows, bottom to top as read by OpenGL
+	buf := []uint8{
+		0xFF, 0, 0, 0xFF, 0xF0, 0xF0, 0xF0, 0xFF,
+		0, 0, 0, 0xFF, 0xFF, 0, 0, 0xFF,
+	}
+	pal := color.Palette{
+		color.RGBA{0, 0, 0, 0xFF},
+		color.RGBA{0xFF, 0, 0, 0xFF},
+		color.RGBA{0xFF, 0xFF, 0xFF, 0xFF},
+	}
+	m := paletted(buf, 2, 2, pal)
+	expected := []uint8{0, 1, 1, 2}
+	for i, c := range expected {
+		if m.Pix[i] != c {
+			t.Errorf("got %v, expected %v", m.Pix, expected)
+			break
+		}
+	}
+
+	m = paletted(buf, 2, 2, nil)
+	if len(m.Palette) != 1 {
+		t.Fatalf("empty palette: got %d entries, expected 1", len(m.Palette))
+	}
+	for _, c := range m.Pix {
+		if c != 0 {
+			t.Errorf("empty palette: got %v, expected all zeros", m.Pix)
+			break
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
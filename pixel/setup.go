@@ -6,6 +6,7 @@ package pixel
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/drakmaniso/carol/x/gl"
 	"github.com/drakmaniso/carol/internal"
@@ -30,6 +31,16 @@ func init() {
 func setupHook() error {
 	var err error
 
+	if internal.Headless {
+		// Without OpenGL context, only keep track of the sizes
+		screen.size = Coord{
+			int16(internal.Config.ScreenSize[0]),
+			int16(internal.Config.ScreenSize[1]),
+		}
+		screen.pixel = internal.Config.PixelSize
+		return loadAllPictures()
+	}
+
 	createScreen()
 
 	stampPipeline = gl.NewPipeline(
@@ -59,8 +70,12 @@ func setupHook() error {
 	}
 
 	fmt.Printf("\n\n%v\n\n", mappings)
-	mappingsTBO = gl.NewBufferTexture(mappings, gl.R16I, gl.StaticStorage)
-	mappingsTBO.Bind(5)
+	uploadMappings()
+
+	if internal.Config.Debug {
+		watcher.stamps = stampPictures()
+		watcher.last = time.Now()
+	}
 
 	return gl.Err()
 }
@@ -6,12 +6,11 @@ package poly
 //------------------------------------------------------------------------------
 
 import (
-	"fmt"
-	"os"
 	"strconv"
 
 	"github.com/drakmaniso/carol/x/gl"
 	"github.com/drakmaniso/carol/formats/obj"
+	"github.com/drakmaniso/carol/internal"
 	"github.com/drakmaniso/carol/space"
 )
 
@@ -57,6 +56,10 @@ var vertexSSBO gl.StorageBuffer
 
 //------------------------------------------------------------------------------
 
+var debug = internal.Logger{System: "poly", Level: internal.DebugLevel}
+
+//------------------------------------------------------------------------------
+
 type Meshes struct {
 	Faces    []Face
 	Vertices []space.Coord
@@ -67,21 +70,23 @@ type MeshID struct {
 	VertexID uint32
 }
 
+// AddObj loads the meshes of an OBJ file, from the asset file system (or
+// directly from disk if filename is an absolute path).
 func (m *Meshes) AddObj(filename string) (MeshID, error) {
 	mid := MeshID{FaceID: uint32(len(m.Faces)), VertexID: uint32(len(m.Vertices))}
 
-	f, err := os.Open(filename)
+	f, err := internal.OpenAsset(filename)
 	if err != nil {
 		return MeshID{}, err //TODO: error wrapping
 	}
+	defer f.Close()
 	b := builder{
 		meshes: m,
 	}
 	obj.Parse(f, &b)
 
-	//TODO: debug log
-	fmt.Printf(
-		"Loaded %s: \n    vertices: %d, faces: %d\n",
+	debug.Printf(
+		"Loaded %s: vertices: %d, faces: %d",
 		filename,
 		len(m.Vertices)-int(mid.VertexID),
 		len(m.Faces)-int(mid.FaceID),
@@ -0,0 +1,432 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package carol
+
+//------------------------------------------------------------------------------
+
+import (
+	"encoding/json"
+	"io"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/drakmaniso/carol/internal"
+	"github.com/drakmaniso/carol/x/gl"
+)
+
+//------------------------------------------------------------------------------
+
+// ProfileFrames is the number of frames kept by the profiler, for statistics
+// and trace export.
+const ProfileFrames = 240
+
+// FrameStats holds the measurements of the profiler for one frame.
+type FrameStats struct {
+	Start      float64 // Start of the frame, in seconds since profiling started
+	Duration   float64 // Time spent in the game loop, in seconds
+	GCCount    uint32  // Number of garbage collections
+	GCPause    float64 // Total duration of garbage collection pauses, in seconds
+	Allocs     uint64  // Number of heap allocations
+	AllocBytes uint64  // Total size of heap allocations
+}
+
+// ProfileStats summarizes the durations of a section over the last
+// ProfileFrames frames. All durations are in seconds.
+type ProfileStats struct {
+	Count        int
+	Average      float64
+	Min, Max     float64
+	Percentile95 float64
+}
+
+//------------------------------------------------------------------------------
+
+type profSection struct {
+	name     string
+	start    float64
+	duration float64
+	depth    int
+	gpu      bool
+}
+
+type profFrame struct {
+	serial   uint64
+	sections []profSection
+	stats    FrameStats
+}
+
+// profRing holds the per-frame durations of a section.
+type profRing struct {
+	values [ProfileFrames]float64
+	count  int
+	next   int
+	sum    float64 // for the current frame
+	used   bool    // during the current frame
+}
+
+// gpuScope is a GPU section waiting for the results of its queries.
+type gpuScope struct {
+	name       string
+	serial     uint64
+	start      float64
+	depth      int
+	begin, end gl.TimerQuery
+}
+
+type profilerState struct {
+	enabled bool
+	origin  time.Time
+
+	frames  [ProfileFrames]profFrame
+	serial  uint64 // of the current frame
+	current *profFrame
+	depth   int
+
+	rings map[string]*profRing
+
+	pending []gpuScope
+	queries []gl.TimerQuery // unused queries
+
+	mem runtime.MemStats
+}
+
+var profiler profilerState
+
+//------------------------------------------------------------------------------
+
+// EnableProfiler starts or stops the profiler. When it's disabled (the
+// default), Profile and ProfileGPU do nothing.
+//
+// Starting the profiler resets all measurements.
+func EnableProfiler(enabled bool) {
+	if enabled && !profiler.enabled {
+		profiler.origin = time.Now()
+		profiler.frames = [ProfileFrames]profFrame{}
+		profiler.serial = 0
+		profiler.current = nil
+		profiler.depth = 0
+		profiler.rings = map[string]*profRing{}
+		runtime.ReadMemStats(&profiler.mem)
+	}
+	profiler.enabled = enabled
+}
+
+// IsProfiling returns true if the profiler is enabled.
+func IsProfiling() bool {
+	return profiler.enabled
+}
+
+func profNow() float64 {
+	return time.Since(profiler.origin).Seconds()
+}
+
+//------------------------------------------------------------------------------
+
+// Profile starts measuring the CPU time of a named section of the frame, and
+// returns a function that ends the measure. It is intended to be used with
+// defer:
+//
+//  defer carol.Profile("physics")()
+//
+// Sections can be nested. A section used several times in a frame (e.g. once
+// per Update step) is summed in the statistics.
+func Profile(name string) func() {
+	if !profiler.enabled || profiler.current == nil {
+		return profNothing
+	}
+	f := profiler.current
+	i := len(f.sections)
+	f.sections = append(f.sections, profSection{
+		name:  name,
+		start: profNow(),
+		depth: profiler.depth,
+	})
+	profiler.depth++
+	serial := profiler.serial
+	return func() {
+		if !profiler.enabled || profiler.serial != serial {
+			return
+		}
+		profiler.depth--
+		s := &f.sections[i]
+		s.duration = profNow() - s.start
+		profiler.ring(name).add(s.duration)
+	}
+}
+
+func profNothing() {}
+
+// ProfileGPU is like Profile, but also measures the GPU time spent in the
+// section, using OpenGL timer queries. The GPU statistics are available under
+// the name of the section followed by " (GPU)", a few frames later.
+//
+// Without OpenGL (i.e. in a headless simulation), it's the same as Profile.
+func ProfileGPU(name string) func() {
+	end := Profile(name)
+	if !profiler.enabled || profiler.current == nil || internal.Headless {
+		return end
+	}
+	g := gpuScope{
+		name:   name + " (GPU)",
+		serial: profiler.serial,
+		start:  profNow(),
+		depth:  profiler.depth - 1,
+		begin:  profiler.query(),
+		end:    profiler.query(),
+	}
+	g.begin.Timestamp()
+	return func() {
+		end()
+		if !profiler.enabled || profiler.serial != g.serial {
+			profiler.queries = append(profiler.queries, g.begin, g.end)
+			return
+		}
+		g.end.Timestamp()
+		profiler.pending = append(profiler.pending, g)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func (p *profRing) add(d float64) {
+	p.sum += d
+	p.used = true
+}
+
+func (p *profRing) push(v float64) {
+	p.values[p.next] = v
+	p.next = (p.next + 1) % ProfileFrames
+	if p.count < ProfileFrames {
+		p.count++
+	}
+}
+
+func (p *profRing) samples() []float64 {
+	s := make([]float64, p.count)
+	copy(s, p.values[:p.count])
+	return s
+}
+
+//------------------------------------------------------------------------------
+
+func (pr *profilerState) ring(name string) *profRing {
+	r := pr.rings[name]
+	if r == nil {
+		r = &profRing{}
+		pr.rings[name] = r
+	}
+	return r
+}
+
+func (pr *profilerState) query() gl.TimerQuery {
+	if n := len(pr.queries); n > 0 {
+		q := pr.queries[n-1]
+		pr.queries = pr.queries[:n-1]
+		return q
+	}
+	return gl.NewTimerQuery()
+}
+
+//------------------------------------------------------------------------------
+
+// profileFrameStart is called by the game loop at the start of each frame.
+func profileFrameStart() {
+	if !profiler.enabled {
+		return
+	}
+	profiler.serial++
+	f := &profiler.frames[profiler.serial%ProfileFrames]
+	f.serial = profiler.serial
+	f.sections = f.sections[:0]
+	f.stats = FrameStats{Start: profNow()}
+	profiler.current = f
+	profiler.depth = 0
+}
+
+// profileFrameEnd is called by the game loop at the end of each frame.
+func profileFrameEnd() {
+	if !profiler.enabled || profiler.current == nil {
+		return
+	}
+	f := profiler.current
+	f.stats.Duration = profNow() - f.stats.Start
+
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	f.stats.GCCount = m.NumGC - profiler.mem.NumGC
+	f.stats.GCPause = float64(m.PauseTotalNs-profiler.mem.PauseTotalNs) / 1e9
+	f.stats.Allocs = m.Mallocs - profiler.mem.Mallocs
+	f.stats.AllocBytes = m.TotalAlloc - profiler.mem.TotalAlloc
+	profiler.mem = m
+
+	profiler.ring("frame").push(f.stats.Duration)
+	for _, r := range profiler.rings {
+		if r.used {
+			r.push(r.sum)
+			r.sum, r.used = 0, false
+		}
+	}
+
+	profileCollectGPU()
+	profiler.current = nil
+}
+
+// profileCollectGPU adds the results of the finished GPU queries.
+func profileCollectGPU() {
+	k := 0
+	for _, g := range profiler.pending {
+		if !g.end.IsAvailable() {
+			profiler.pending[k] = g
+			k++
+			continue
+		}
+		d := float64(g.end.Result()-g.begin.Result()) / 1e9
+		profiler.queries = append(profiler.queries, g.begin, g.end)
+		profiler.ring(g.name).push(d)
+		f := &profiler.frames[g.serial%ProfileFrames]
+		if f.serial == g.serial {
+			f.sections = append(f.sections, profSection{
+				name:     g.name,
+				start:    g.start,
+				duration: d,
+				depth:    g.depth,
+				gpu:      true,
+			})
+		}
+	}
+	profiler.pending = profiler.pending[:k]
+}
+
+//------------------------------------------------------------------------------
+
+// LastFrameStats returns the measurements of the last complete frame.
+func LastFrameStats() FrameStats {
+	if !profiler.enabled || profiler.serial == 0 {
+		return FrameStats{}
+	}
+	s := profiler.serial
+	if profiler.current != nil {
+		s--
+	}
+	return profiler.frames[s%ProfileFrames].stats
+}
+
+// ProfileSummary returns the statistics of all profiled sections, over the
+// last ProfileFrames frames. The whole frame is included under the name
+// "frame".
+func ProfileSummary() map[string]ProfileStats {
+	m := make(map[string]ProfileStats, len(profiler.rings))
+	for n, r := range profiler.rings {
+		if r.count == 0 {
+			continue
+		}
+		s := r.samples()
+		sort.Float64s(s)
+		st := ProfileStats{
+			Count:        len(s),
+			Min:          s[0],
+			Max:          s[len(s)-1],
+			Percentile95: s[(len(s)*95)/100],
+		}
+		for _, v := range s {
+			st.Average += v
+		}
+		st.Average /= float64(len(s))
+		m[n] = st
+	}
+	return m
+}
+
+// ProfileHistogram returns the distribution of the durations of a section
+// over the last ProfileFrames frames: each bucket counts the frames where the
+// section lasted between i*width and (i+1)*width seconds. The last bucket also
+// counts all longer durations.
+func ProfileHistogram(name string, width float64, buckets int) []int {
+	h := make([]int, buckets)
+	r := profiler.rings[name]
+	if r == nil || buckets < 1 {
+		return h
+	}
+	for _, v := range r.samples() {
+		i := int(v / width)
+		if i >= buckets {
+			i = buckets - 1
+		}
+		h[i]++
+	}
+	return h
+}
+
+//------------------------------------------------------------------------------
+
+type traceEvent struct {
+	Name      string                 `json:"name"`
+	Phase     string                 `json:"ph"`
+	Timestamp float64                `json:"ts"`
+	Duration  float64                `json:"dur,omitempty"`
+	Process   int                    `json:"pid"`
+	Thread    int                    `json:"tid"`
+	Args      map[string]interface{} `json:"args,omitempty"`
+}
+
+// WriteTrace writes the last ProfileFrames frames in the Chrome trace event
+// format (JSON), which can be opened in chrome://tracing or similar tools.
+// CPU sections are shown on the first thread, and GPU sections on the second.
+func WriteTrace(w io.Writer) error {
+	const us = 1e6
+	ev := []traceEvent{
+		{Name: "thread_name", Phase: "M", Process: 1, Thread: 1, Args: map[string]interface{}{"name": "CPU"}},
+		{Name: "thread_name", Phase: "M", Process: 1, Thread: 2, Args: map[string]interface{}{"name": "GPU"}},
+	}
+	first := uint64(1)
+	if profiler.serial > ProfileFrames {
+		first = profiler.serial - ProfileFrames + 1
+	}
+	for s := first; s <= profiler.serial; s++ {
+		f := &profiler.frames[s%ProfileFrames]
+		if f.serial != s || f == profiler.current {
+			continue
+		}
+		ev = append(ev, traceEvent{
+			Name:      "frame",
+			Phase:     "X",
+			Timestamp: f.stats.Start * us,
+			Duration:  f.stats.Duration * us,
+			Process:   1,
+			Thread:    1,
+		})
+		ev = append(ev, traceEvent{
+			Name:      "memory",
+			Phase:     "C",
+			Timestamp: f.stats.Start * us,
+			Process:   1,
+			Args: map[string]interface{}{
+				"allocs":   f.stats.Allocs,
+				"gc pause": f.stats.GCPause * us,
+			},
+		})
+		for _, sc := range f.sections {
+			t := 1
+			if sc.gpu {
+				t = 2
+			}
+			ev = append(ev, traceEvent{
+				Name:      sc.name,
+				Phase:     "X",
+				Timestamp: sc.start * us,
+				Duration:  sc.duration * us,
+				Process:   1,
+				Thread:    t,
+			})
+		}
+	}
+
+	return json.NewEncoder(w).Encode(struct {
+		TraceEvents     []traceEvent `json:"traceEvents"`
+		DisplayTimeUnit string       `json:"displayTimeUnit"`
+	}{ev, "ms"})
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,78 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package carol_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/drakmaniso/carol"
+)
+
+//------------------------------------------------------------------------------
+
+type profiledLoop struct {
+	countingLoop
+}
+
+func (l *profiledLoop) Update() error {
+	defer carol.Profile("physics")()
+	return l.countingLoop.Update()
+}
+
+func TestProfiler(t *testing.T) {
+	var l profiledLoop
+	s, err := carol.Simulate(&l, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	carol.EnableProfiler(true)
+	defer carol.EnableProfiler(false)
+	s.Step(30)
+
+	sum := carol.ProfileSummary()
+	for _, n := range []string{"frame", "events", "update", "physics", "draw", "pixel"} {
+		if sum[n].Count != 30 {
+			t.Errorf("section %q measured %d times, expected 30", n, sum[n].Count)
+		}
+	}
+	h := carol.ProfileHistogram("frame", 0.001, 10)
+	c := 0
+	for _, v := range h {
+		c += v
+	}
+	if c != 30 {
+		t.Errorf("histogram counts %d frames, expected 30", c)
+	}
+
+	var buf bytes.Buffer
+	err = carol.WriteTrace(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var trace struct {
+		TraceEvents []struct {
+			Name  string
+			Phase string `json:"ph"`
+		}
+	}
+	err = json.Unmarshal(buf.Bytes(), &trace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for _, e := range trace.TraceEvents {
+		if e.Name == "physics" && e.Phase == "X" {
+			n++
+		}
+	}
+	if n != 30 {
+		t.Errorf("trace has %d physics events, expected 30", n)
+	}
+}
+
+//------------------------------------------------------------------------------
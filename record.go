@@ -0,0 +1,274 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package carol
+
+//------------------------------------------------------------------------------
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/drakmaniso/carol/internal"
+)
+
+//------------------------------------------------------------------------------
+
+// recordVersion is the version of the recording format.
+const recordVersion = 1
+
+// recordHeader is the first line of a recording.
+type recordHeader struct {
+	Version  int
+	TimeStep float64
+	Start    float64
+}
+
+// A RecordEntry is an event of a recording, tagged with the index of the fixed
+// time step it arrived in (i.e. the number of Update calls made before its
+// dispatch).
+type RecordEntry struct {
+	Step  uint64
+	Event Event
+}
+
+//------------------------------------------------------------------------------
+
+// Record saves all the events dispatched during the run to w, so that the
+// session can be reproduced with Replay.
+//
+// The recording is a sequence of JSON values: a header with the time step and
+// start time, followed by one line per event.
+func Record(w io.Writer) Option {
+	return func(*Config) {
+		session.record = w
+	}
+}
+
+// Replay makes the run take the user input from a recording (made with Record)
+// instead of the live devices. The game loop is driven by a virtual clock
+// advancing exactly one time step per frame, so that the sequence of Update
+// calls is the same as in the recorded session. Live window events are still
+// dispatched.
+//
+// Once the recording is exhausted, the game loop continues with live input. If
+// interruptible is true, a live key or mouse button press also ends the replay
+// (and is otherwise ignored), which is useful for attract-mode demos.
+func Replay(r io.Reader, interruptible bool) Option {
+	return func(*Config) {
+		session.replay = r
+		session.interruptible = interruptible
+	}
+}
+
+// Replaying returns true while the game loop is driven by a recording.
+func Replaying() bool {
+	return session.replaying != nil
+}
+
+// StopReplay ends the current replay (if any) at the end of the frame; the
+// game loop then continues with live input.
+func StopReplay() {
+	if session.replaying != nil {
+		session.stopReplay = true
+	}
+}
+
+// endReplay switches back to live input. Must be called between frames.
+func endReplay() {
+	session.replaying = nil
+	session.stopReplay = false
+	internal.FilterEvent = nil
+	// Back to the real clock, without restarting the step count
+	steps := loopTime.steps
+	resetLoopTime(internal.GetSeconds())
+	loopTime.steps = steps
+}
+
+//------------------------------------------------------------------------------
+
+var session struct {
+	record        io.Writer
+	replay        io.Reader
+	interruptible bool
+
+	writer    *bufio.Writer
+	encoder   *json.Encoder
+	recordErr error
+
+	replaying  *Recording
+	stopReplay bool
+	clock      VirtualClock
+}
+
+// startSession starts the recording and/or replay requested by the options.
+// Must be called after resetLoopTime.
+func startSession() error {
+	if session.replay != nil {
+		r, err := ReadRecording(session.replay)
+		session.replay = nil
+		if err != nil {
+			return internal.Error("in replay", err)
+		}
+		session.replaying = r
+		session.stopReplay = false
+		session.clock.reset(r.Start)
+		timeStep = r.TimeStep
+		resetLoopTime(r.Start)
+		internal.FilterEvent = filterLive
+	}
+
+	if session.record != nil {
+		startRecording(session.record)
+		session.record = nil
+	}
+
+	return nil
+}
+
+// sessionFrame runs one frame of the game loop, either with live input or from
+// the replay.
+func sessionFrame() error {
+	r := session.replaying
+	if r == nil {
+		return frame(internal.GetSeconds(), internal.ProcessEvents)
+	}
+
+	session.clock.tick()
+	err := frame(session.clock.Seconds(), func() {
+		internal.ProcessEvents()
+		for _, e := range r.Events(session.clock.Seconds()) {
+			if e.Kind.IsInput() {
+				internal.Dispatch(e)
+			}
+		}
+	})
+	if r.Done() || session.stopReplay {
+		endReplay()
+	}
+	return err
+}
+
+// filterLive drops the live input events during a replay.
+func filterLive(e Event) bool {
+	if !e.Kind.IsInput() {
+		return true
+	}
+	if session.interruptible &&
+		(e.Kind == EventKeyDown || e.Kind == EventMouseButtonDown) {
+		StopReplay()
+	}
+	return false
+}
+
+//------------------------------------------------------------------------------
+
+func startRecording(w io.Writer) {
+	session.writer = bufio.NewWriter(w)
+	session.encoder = json.NewEncoder(session.writer)
+	session.recordErr = session.encoder.Encode(recordHeader{
+		Version:  recordVersion,
+		TimeStep: timeStep,
+		Start:    loopTime.stepNow,
+	})
+	internal.RecordEvent = recordEvent
+}
+
+func recordEvent(e Event) {
+	if session.recordErr != nil {
+		return
+	}
+	session.recordErr = session.encoder.Encode(RecordEntry{
+		Step:  loopTime.steps,
+		Event: e,
+	})
+}
+
+// stopRecording flushes the recording, and returns the first error that
+// occured while writing it.
+func stopRecording() error {
+	if session.writer == nil {
+		return nil
+	}
+	internal.RecordEvent = nil
+	err := session.writer.Flush()
+	if session.recordErr == nil {
+		session.recordErr = err
+	}
+	err = session.recordErr
+	session.writer, session.encoder, session.recordErr = nil, nil, nil
+	return internal.Error("in recording", err)
+}
+
+//------------------------------------------------------------------------------
+
+// A Recording is a sequence of events read from a file made with Record.
+//
+// It implements EventSource, so that it can be used to replay a session in a
+// Simulation: each event is delivered before the Update call with the same
+// index as during the recording.
+type Recording struct {
+	TimeStep float64
+	Start    float64
+	Entries  []RecordEntry
+	next     int
+}
+
+// ReadRecording reads a recording made with Record.
+func ReadRecording(r io.Reader) (*Recording, error) {
+	d := json.NewDecoder(r)
+
+	var h recordHeader
+	err := d.Decode(&h)
+	if err != nil {
+		return nil, internal.Error("in recording header", err)
+	}
+	if h.Version != recordVersion {
+		return nil, errors.New("unsupported recording version")
+	}
+	if h.TimeStep <= 0 {
+		return nil, errors.New("invalid time step in recording")
+	}
+
+	rec := &Recording{
+		TimeStep: h.TimeStep,
+		Start:    h.Start,
+	}
+	for {
+		var e RecordEntry
+		err := d.Decode(&e)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, internal.Error("in recording", err)
+		}
+		rec.Entries = append(rec.Entries, e)
+	}
+
+	return rec, nil
+}
+
+// Events returns the events that arrived before the current Update call.
+func (r *Recording) Events(now float64) []Event {
+	var e []Event
+	for r.next < len(r.Entries) && r.Entries[r.next].Step <= loopTime.steps {
+		e = append(e, r.Entries[r.next].Event)
+		r.next++
+	}
+	return e
+}
+
+// Done returns true once all events have been delivered.
+func (r *Recording) Done() bool {
+	return r.next >= len(r.Entries)
+}
+
+// Rewind restarts the recording from the beginning.
+func (r *Recording) Rewind() {
+	r.next = 0
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,72 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package carol_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/drakmaniso/carol"
+	"github.com/drakmaniso/carol/key"
+)
+
+//------------------------------------------------------------------------------
+
+func TestRecordReplay(t *testing.T) {
+	ts := carol.TimeStep()
+	script := carol.Script{
+		{Kind: carol.EventKeyDown, Time: 3 * ts, Label: key.LabelSpace, Position: key.PositionSpace},
+		{Kind: carol.EventKeyUp, Time: 7 * ts, Label: key.LabelSpace, Position: key.PositionSpace},
+		{Kind: carol.EventKeyDown, Time: 20 * ts, Label: key.LabelSpace, Position: key.PositionSpace},
+		{Kind: carol.EventKeyUp, Time: 21 * ts, Label: key.LabelSpace, Position: key.PositionSpace},
+	}
+
+	// Record
+
+	var buf bytes.Buffer
+	var l1 countingLoop
+	s, err := carol.Simulate(&l1, &script, false, carol.Record(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.Step(30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Replay
+
+	r, err := carol.ReadRecording(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Entries) != 4 {
+		t.Fatalf("recorded %d events, expected 4", len(r.Entries))
+	}
+
+	var l2 countingLoop
+	s, err = carol.Simulate(&l2, r, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	err = s.Step(30)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if l1.jumps == 0 || l1.jumps != l2.jumps || l1.updates != l2.updates {
+		t.Errorf("replay mismatch: recorded %d jumps in %d updates, replayed %d in %d",
+			l1.jumps, l1.updates, l2.jumps, l2.updates)
+	}
+	if !r.Done() {
+		t.Errorf("recording not exhausted")
+	}
+}
+
+//------------------------------------------------------------------------------
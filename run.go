@@ -5,9 +5,11 @@ package carol
 
 import (
 	"github.com/drakmaniso/carol/x/gl"
+	"github.com/drakmaniso/carol/gamepad"
 	"github.com/drakmaniso/carol/internal"
 	"github.com/drakmaniso/carol/key"
 	"github.com/drakmaniso/carol/mouse"
+	"github.com/drakmaniso/carol/touch"
 )
 
 //------------------------------------------------------------------------------
@@ -45,6 +47,28 @@ type GameLoop interface {
 	MouseButtonUp(b mouse.Button, clicks int)
 	MouseWheel(deltaX, deltaY int32)
 
+	// Gamepad events
+	GamepadConnected(pad int)
+	GamepadDisconnected(pad int)
+	GamepadButtonDown(pad int, b gamepad.Button)
+	GamepadButtonUp(pad int, b gamepad.Button)
+	GamepadAxisMotion(pad int, a gamepad.Axis, value float32)
+
+	// Text input events
+	TextInput(text string)
+	TextEditing(text string, start, length int)
+
+	// Drag and drop events
+	FileDropped(path string)
+	TextDropped(text string)
+
+	// Touch events
+	TouchDown(t touch.Touch)
+	TouchMove(t touch.Touch)
+	TouchUp(t touch.Touch)
+	Pinch(scale float32, x, y int16)
+	Rotate(angle float32, x, y int16)
+
 	// Pixel events
 	ScreenResized(width, height int16, pixel int32)
 }
@@ -81,17 +105,33 @@ var timeStep = float64(1.0 / 60)
 // and the draw callback are called once for each frame displayed. The loop runs
 // until Stop() is called.
 //
+// The options are applied on top of the default configuration, before the
+// configuration file, environment variables and command-line arguments (see
+// Config).
+//
+// If the game panics, Run recovers, saves a crash report in the user data
+// directory (see UserDataDir), shows an error dialog (except in debug mode),
+// and returns an error.
+//
 // Important: must be called from main.main, or at least from a function that is
 // known to run on the main OS thread.
-func Run(loop GameLoop) error {
+func Run(loop GameLoop, options ...Option) (err error) {
 	defer internal.SDLQuit()
 	defer internal.DestroyWindow()
+	defer internal.AudioQuit()
+	defer internal.TweenQuit()
+	defer internal.ScriptQuit()
+	defer recoverCrash(&err)
 
 	internal.Loop = loop
 
+	for _, o := range options {
+		o(&internal.Config)
+	}
+
 	// Setup
 
-	err := internal.Setup()
+	err = internal.Setup()
 	if err != nil {
 		return internal.Error("in internal setup", err)
 	}
@@ -100,12 +140,23 @@ func Run(loop GameLoop) error {
 	if err != nil {
 		return internal.Error("in OpenGL setup", err)
 	}
+	gl.SetLogger(internal.Logger{System: "gl", Level: internal.DebugLevel})
 
 	err = internal.PixelSetup()
 	if err != nil {
 		return internal.Error("in pixel Setup", err)
 	}
 
+	err = internal.TextSetup()
+	if err != nil {
+		return internal.Error("in text Setup", err)
+	}
+
+	err = internal.AudioSetup()
+	if err != nil {
+		return internal.Error("in audio Setup", err)
+	}
+
 	err = internal.Loop.Setup()
 	if err != nil {
 		return internal.Error("in game loop Setup", err)
@@ -117,54 +168,115 @@ func Run(loop GameLoop) error {
 
 	// Main Loop
 
-	then := internal.GetSeconds()
-	now := then
-	stepNow := now
-	remain := 0.0
+	resetLoopTime(internal.GetSeconds())
+
+	err = startSession()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err := stopRecording()
+		if err != nil {
+			internal.ErrorLog.Printf("%s", err)
+		}
+	}()
 
 	for !internal.QuitRequested {
-		now = internal.GetSeconds()
-		delta = now - then
-		//TODO: clamp delta ?
-		countFrames()
+		err = sessionFrame()
+		if err != nil {
+			return err
+		}
+
+		captureWindow()
+		internal.SwapWindow()
+	}
+	return nil
+}
 
-		internal.ProcessEvents() //TODO: Should it be in the physisc loop?
+//------------------------------------------------------------------------------
 
-		// Update with fixed time step
+// loopTime holds the timing state of the game loop.
+var loopTime struct {
+	then    float64
+	stepNow float64
+	remain  float64
+	steps   uint64 // number of Update calls since the start
+}
 
-		remain += delta
-		// Cap remain to avoid "spiral of death"
-		for remain > 8*timeStep {
-			remain -= timeStep
-			stepNow += timeStep
-		}
-		for remain >= timeStep {
-			internal.VisibleNow = stepNow
-			err = internal.Loop.Update()
-			if err != nil {
-				return internal.Error("in Update callback", err)
-			}
-			remain -= timeStep
-			stepNow += timeStep
-		}
+func resetLoopTime(now float64) {
+	loopTime.then = now
+	loopTime.stepNow = now
+	loopTime.remain = 0.0
+	loopTime.steps = 0
+}
+
+// frame runs one iteration of the game loop: process the events, update with
+// fixed time step, and draw.
+func frame(now float64, events func()) error {
+	profileFrameStart()
+	defer profileFrameEnd()
 
-		// Draw
+	delta = now - loopTime.then
+	//TODO: clamp delta ?
+	countFrames()
+	internal.FrameNumber++
 
-		internal.VisibleNow = now
-		err = internal.Loop.Draw(delta, remain/timeStep)
+	end := Profile("events")
+	events() //TODO: Should it be in the physisc loop?
+	end()
+
+	// Update with fixed time step
+
+	loopTime.remain += delta
+	// Cap remain to avoid "spiral of death"
+	for loopTime.remain > 8*timeStep {
+		loopTime.remain -= timeStep
+		loopTime.stepNow += timeStep
+	}
+	// (with a small tolerance for rounding errors in the clock, so that a frame
+	// lasting one time step always runs exactly one Update)
+	for loopTime.remain >= timeStep*(1-1.0/1024) {
+		internal.VisibleNow = loopTime.stepNow
+		internal.LatchInput(loopTime.stepNow)
+		end := Profile("update")
+		internal.TweenStep()
+		err := internal.ScriptStep()
 		if err != nil {
-			return internal.Error("in Draw callback", err)
+			return err
 		}
-
-		err = internal.PixelDraw()
+		err = internal.Loop.Update()
+		end()
 		if err != nil {
-			return internal.Error("in pixel Draw", err)
+			return internal.Error("in Update callback", err)
 		}
+		loopTime.remain -= timeStep
+		loopTime.stepNow += timeStep
+		loopTime.steps++
+	}
 
-		internal.SwapWindow()
+	// Draw
 
-		then = now
+	internal.VisibleNow = now
+	lerp := loopTime.remain / timeStep
+	if lerp < 0 {
+		// Because of the tolerance above
+		lerp = 0
 	}
+	end = Profile("draw")
+	err := internal.Loop.Draw(delta, lerp)
+	end()
+	if err != nil {
+		return internal.Error("in Draw callback", err)
+	}
+
+	end = ProfileGPU("pixel")
+	err = internal.PixelDraw()
+	end()
+	if err != nil {
+		return internal.Error("in pixel Draw", err)
+	}
+
+	loopTime.then = now
 	return nil
 }
 
@@ -223,7 +335,13 @@ var frAverage float64
 var frSum float64
 var frCount int
 
-const xrunThreshold float64 = 17 / 1000.0
+var xrunThreshold float64 = 17 / 1000.0
+
+// SetOverrunThreshold changes the frame duration above which a frame is
+// counted as an overrun by FrameTimeAverage (17ms by default).
+func SetOverrunThreshold(t float64) {
+	xrunThreshold = t
+}
 
 var xrunCount, xrunPrevious int
 
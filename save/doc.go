@@ -0,0 +1,25 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+/*
+Package save stores game data in named slots, inside the user data directory.
+
+  var state GameState
+  err := save.Slot("slot1").Save(&state)
+  ...
+  err = save.Slot("slot1").Load(&state)
+
+The data is encoded in JSON, along with the version of the format and a
+checksum. Each save is first written to a temporary file, then renamed: a crash
+never leaves a half-written file. The previous saves are kept as backups, and
+used when the newest file is corrupted.
+
+When the format of the data changes, increase the version, and register a
+migration to convert the older saves:
+
+  save.SetVersion(2)
+  save.Migrate(1, func(data []byte) ([]byte, error) {
+  	...
+  })
+*/
+package save
@@ -0,0 +1,144 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package save
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+//------------------------------------------------------------------------------
+
+type state struct {
+	Level int
+	Name  string `json:",omitempty"`
+}
+
+func setup(t *testing.T) string {
+	d, err := ioutil.TempDir("", "carol-save")
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev, set := os.LookupEnv("XDG_DATA_HOME")
+	os.Setenv("XDG_DATA_HOME", d)
+	t.Cleanup(func() {
+		if set {
+			os.Setenv("XDG_DATA_HOME", prev)
+		} else {
+			os.Unsetenv("XDG_DATA_HOME")
+		}
+		os.RemoveAll(d)
+		SetVersion(1)
+		migrations = map[int]func([]byte) ([]byte, error){}
+	})
+	return d
+}
+
+func TestSaveLoad(t *testing.T) {
+	setup(t)
+	s := Slot("test")
+
+	var v state
+	err := s.Load(&v)
+	if err != ErrNotFound {
+		t.Fatalf("loading empty slot: got %v", err)
+	}
+
+	for i := 1; i <= 5; i++ {
+		err := s.Save(state{Level: i})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = s.Load(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Level != 5 {
+		t.Errorf("got level %d, want 5", v.Level)
+	}
+
+	p, _ := s.path()
+	for i := 1; i <= 4; i++ {
+		_, err := os.Stat(backup(p, i))
+		if (i <= 3) != (err == nil) {
+			t.Errorf("backup %d: unexpected stat result %v", i, err)
+		}
+	}
+
+	l, err := Slots()
+	if err != nil || len(l) != 1 || l[0] != s {
+		t.Errorf("Slots: got %v, %v", l, err)
+	}
+
+	// Corrupt the save: the first backup should be used
+	b, _ := ioutil.ReadFile(p)
+	b[len(b)/2] ^= 1
+	ioutil.WriteFile(p, b, 0644)
+	err = s.Load(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Level != 4 {
+		t.Errorf("got level %d after corruption, want 4", v.Level)
+	}
+
+	err = s.Delete()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Exists() {
+		t.Errorf("slot still exists after Delete")
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	setup(t)
+	s := Slot("old")
+
+	err := s.Save(map[string]int{"Lvl": 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	SetVersion(2)
+	var v state
+	err = s.Load(&v)
+	if err == nil {
+		t.Fatalf("loading without migration: no error")
+	}
+
+	Migrate(1, func(data []byte) ([]byte, error) {
+		var o map[string]int
+		err := json.Unmarshal(data, &o)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(state{Level: o["Lvl"], Name: "migrated"})
+	})
+	err = s.Load(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Level != 7 || v.Name != "migrated" {
+		t.Errorf("got %+v after migration", v)
+	}
+}
+
+func TestInvalidSlot(t *testing.T) {
+	d := setup(t)
+	err := Slot("../escape").Save(state{})
+	if err == nil {
+		t.Errorf("invalid slot name accepted")
+	}
+	_, err = os.Stat(filepath.Join(d, "escape.json"))
+	if !os.IsNotExist(err) {
+		t.Errorf("file written outside of the save directory")
+	}
+}
+
+//------------------------------------------------------------------------------
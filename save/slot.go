@@ -0,0 +1,304 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package save
+
+//------------------------------------------------------------------------------
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/drakmaniso/carol"
+	"github.com/drakmaniso/carol/internal"
+)
+
+//------------------------------------------------------------------------------
+
+// ErrCorrupted is the cause of the error returned when a save file (and all its
+// backups) fails the checksum verification or can't be decoded.
+var ErrCorrupted = errors.New("corrupted save data")
+
+// ErrNotFound is returned when loading an empty slot.
+var ErrNotFound = errors.New("empty save slot")
+
+//------------------------------------------------------------------------------
+
+var warning = internal.Logger{System: "save", Level: internal.WarningLevel}
+
+//------------------------------------------------------------------------------
+
+// dirName is the name of the directory of the save files, inside the user data
+// directory.
+const dirName = "saves"
+
+// extension of the save files.
+const extension = ".json"
+
+var (
+	version    = 1
+	migrations = map[int]func(data []byte) ([]byte, error){}
+	backups    = 3
+)
+
+// SetVersion changes the current version of the save format (1 by default).
+func SetVersion(v int) {
+	version = v
+}
+
+// Migrate registers a function converting the data of a save from a version
+// of the format to the next one. When loading, all migrations are applied in
+// turn, until the data is at the current version.
+func Migrate(from int, f func(data []byte) ([]byte, error)) {
+	migrations[from] = f
+}
+
+// SetBackups changes the number of previous saves kept for each slot (3 by
+// default).
+func SetBackups(n int) {
+	backups = n
+}
+
+//------------------------------------------------------------------------------
+
+// A Slot is a named save file. The name must be a valid file name.
+type Slot string
+
+// file is the content of a save file.
+type file struct {
+	Version  int
+	Checksum string
+	Data     json.RawMessage
+}
+
+// Slots returns all the slots that contain a save.
+func Slots() ([]Slot, error) {
+	d, err := dir()
+	if err != nil {
+		return nil, err
+	}
+	m, err := filepath.Glob(filepath.Join(d, "*"+extension))
+	if err != nil {
+		return nil, err
+	}
+	s := make([]Slot, len(m))
+	for i := range m {
+		s[i] = Slot(strings.TrimSuffix(filepath.Base(m[i]), extension))
+	}
+	sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
+	return s, nil
+}
+
+//------------------------------------------------------------------------------
+
+// Save encodes v in JSON, and writes it to the slot. The previous save is kept
+// as a backup.
+func (s Slot) Save(v interface{}) error {
+	p, err := s.path()
+	if err != nil {
+		return internal.Error(`while saving slot "`+string(s)+`"`, err)
+	}
+
+	d, err := json.Marshal(v)
+	if err != nil {
+		return internal.Error(`while encoding slot "`+string(s)+`"`, err)
+	}
+	b, err := json.MarshalIndent(file{
+		Version:  version,
+		Checksum: checksum(d),
+		Data:     d,
+	}, "", "\t")
+	if err != nil {
+		return internal.Error(`while encoding slot "`+string(s)+`"`, err)
+	}
+
+	err = writeFile(p, b)
+	if err != nil {
+		return internal.Error(`while saving slot "`+string(s)+`"`, err)
+	}
+	return nil
+}
+
+// writeFile writes to a temporary file, then renames it to path, after
+// rotating the backups.
+func writeFile(path string, data []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if err2 := f.Close(); err == nil {
+		err = err2
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	if backups > 0 {
+		for i := backups - 1; i > 0; i-- {
+			err := os.Rename(backup(path, i), backup(path, i+1))
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		err := os.Rename(path, backup(path, 1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+//------------------------------------------------------------------------------
+
+// Load reads the slot, and decodes it into v, after applying the migrations.
+// If the save is corrupted, the most recent valid backup is used instead.
+func (s Slot) Load(v interface{}) error {
+	p, err := s.path()
+	if err != nil {
+		return internal.Error(`while loading slot "`+string(s)+`"`, err)
+	}
+
+	var first error
+	for i := 0; i <= backups; i++ {
+		f := p
+		if i > 0 {
+			f = backup(p, i)
+		}
+		d, err := readFile(f)
+		switch {
+		case err == nil:
+			if i > 0 {
+				warning.Printf("Slot %q: unable to load the save, using backup %d", s, i)
+			}
+			err = json.Unmarshal(d, v)
+			if err != nil {
+				return internal.Error(`while decoding slot "`+string(s)+`"`, err)
+			}
+			return nil
+		case os.IsNotExist(err):
+			// The save may be missing if the game crashed while saving
+			continue
+		case first == nil:
+			first = err
+		}
+	}
+	if first == nil {
+		return ErrNotFound
+	}
+	return internal.Error(`while loading slot "`+string(s)+`"`, first)
+}
+
+// readFile returns the data of a save file, after verification and migration.
+func readFile(path string) ([]byte, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var f file
+	err = json.Unmarshal(b, &f)
+	if err != nil {
+		return nil, ErrCorrupted
+	}
+	// The data is indented in the file
+	var d bytes.Buffer
+	err = json.Compact(&d, f.Data)
+	if err != nil || f.Checksum != checksum(d.Bytes()) {
+		return nil, ErrCorrupted
+	}
+
+	data := d.Bytes()
+	if f.Version > version {
+		return nil, fmt.Errorf("save format version %d is newer than %d", f.Version, version)
+	}
+	for v := f.Version; v < version; v++ {
+		m, ok := migrations[v]
+		if !ok {
+			return nil, fmt.Errorf("no migration from save format version %d", v)
+		}
+		data, err = m(data)
+		if err != nil {
+			return nil, fmt.Errorf("in migration from save format version %d: %w", v, err)
+		}
+	}
+	return data, nil
+}
+
+//------------------------------------------------------------------------------
+
+// Exists returns true if the slot contains a save.
+func (s Slot) Exists() bool {
+	p, err := s.path()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(p)
+	return err == nil
+}
+
+// Delete removes the save and all its backups.
+func (s Slot) Delete() error {
+	p, err := s.path()
+	if err != nil {
+		return internal.Error(`while deleting slot "`+string(s)+`"`, err)
+	}
+	for i := 0; i <= backups; i++ {
+		f := p
+		if i > 0 {
+			f = backup(p, i)
+		}
+		err := os.Remove(f)
+		if err != nil && !os.IsNotExist(err) {
+			return internal.Error(`while deleting slot "`+string(s)+`"`, err)
+		}
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+func (s Slot) path() (string, error) {
+	if s == "" || strings.ContainsAny(string(s), `/\:`) || s == "." || s == ".." {
+		return "", fmt.Errorf("invalid slot name %q", string(s))
+	}
+	d, err := dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(d, string(s)+extension), nil
+}
+
+func dir() (string, error) {
+	d, err := carol.UserDataDir()
+	if err != nil {
+		return "", err
+	}
+	d = filepath.Join(d, dirName)
+	return d, os.MkdirAll(d, 0755)
+}
+
+func backup(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
+
+func checksum(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
+
+//------------------------------------------------------------------------------
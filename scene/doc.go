@@ -0,0 +1,35 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+/*
+Package scene provides a stack of scenes, to keep menus, pause screens and
+levels separate.
+
+A Stack implements carol.GameLoop, and forwards the callbacks to the scenes it
+contains:
+
+  type menu struct {
+    scene.Handlers
+  }
+
+  func (m *menu) KeyDown(l key.Label, p key.Position) {
+    if l == key.LabelReturn {
+      stack.Replace(&level{}, 0.5)
+    }
+  }
+
+  var stack = scene.NewStack(&menu{})
+
+  func main() {
+    err := carol.Run(stack)
+    ...
+  }
+
+Input events and Update only go to the scene at the top of the stack, unless
+it passes them through (see Scene.PassThrough). Window, screen and gamepad
+connection events go to all scenes.
+
+Changes to the stack are applied at the start of the next Update step, one at
+a time, and may take some time (see Stack.Transition).
+*/
+package scene
@@ -0,0 +1,209 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package scene
+
+//------------------------------------------------------------------------------
+
+import (
+	"github.com/drakmaniso/carol/gamepad"
+	"github.com/drakmaniso/carol/key"
+	"github.com/drakmaniso/carol/mouse"
+	"github.com/drakmaniso/carol/touch"
+)
+
+//------------------------------------------------------------------------------
+
+// input calls f for the top scene, and the scenes below as long as they pass
+// through. Input is ignored during transitions.
+func (s *Stack) input(f func(sc Scene)) {
+	if s.current != nil {
+		return
+	}
+	for i := len(s.scenes) - 1; i >= 0; i-- {
+		f(s.scenes[i])
+		if !s.scenes[i].PassThrough() {
+			break
+		}
+	}
+}
+
+// all calls f for every scene, from bottom to top.
+func (s *Stack) all(f func(sc Scene)) {
+	for _, sc := range s.scenes {
+		f(sc)
+	}
+	if s.replacing() {
+		f(s.to)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// WindowShown is sent to all scenes.
+func (s *Stack) WindowShown() {
+	s.all(func(sc Scene) { sc.WindowShown() })
+}
+
+// WindowHidden is sent to all scenes.
+func (s *Stack) WindowHidden() {
+	s.all(func(sc Scene) { sc.WindowHidden() })
+}
+
+// WindowResized is sent to all scenes.
+func (s *Stack) WindowResized(width, height int32) {
+	s.all(func(sc Scene) { sc.WindowResized(width, height) })
+}
+
+// WindowMinimized is sent to all scenes.
+func (s *Stack) WindowMinimized() {
+	s.all(func(sc Scene) { sc.WindowMinimized() })
+}
+
+// WindowMaximized is sent to all scenes.
+func (s *Stack) WindowMaximized() {
+	s.all(func(sc Scene) { sc.WindowMaximized() })
+}
+
+// WindowRestored is sent to all scenes.
+func (s *Stack) WindowRestored() {
+	s.all(func(sc Scene) { sc.WindowRestored() })
+}
+
+// WindowMouseEnter is sent to all scenes.
+func (s *Stack) WindowMouseEnter() {
+	s.all(func(sc Scene) { sc.WindowMouseEnter() })
+}
+
+// WindowMouseLeave is sent to all scenes.
+func (s *Stack) WindowMouseLeave() {
+	s.all(func(sc Scene) { sc.WindowMouseLeave() })
+}
+
+// WindowFocusGained is sent to all scenes.
+func (s *Stack) WindowFocusGained() {
+	s.all(func(sc Scene) { sc.WindowFocusGained() })
+}
+
+// WindowFocusLost is sent to all scenes.
+func (s *Stack) WindowFocusLost() {
+	s.all(func(sc Scene) { sc.WindowFocusLost() })
+}
+
+// WindowQuit is sent to all scenes.
+func (s *Stack) WindowQuit() {
+	s.all(func(sc Scene) { sc.WindowQuit() })
+}
+
+//------------------------------------------------------------------------------
+
+// KeyDown is sent to the top scene.
+func (s *Stack) KeyDown(l key.Label, p key.Position) {
+	s.input(func(sc Scene) { sc.KeyDown(l, p) })
+}
+
+// KeyUp is sent to the top scene.
+func (s *Stack) KeyUp(l key.Label, p key.Position) {
+	s.input(func(sc Scene) { sc.KeyUp(l, p) })
+}
+
+// MouseMotion is sent to the top scene.
+func (s *Stack) MouseMotion(deltaX, deltaY int32, posX, posY int32) {
+	s.input(func(sc Scene) { sc.MouseMotion(deltaX, deltaY, posX, posY) })
+}
+
+// MouseButtonDown is sent to the top scene.
+func (s *Stack) MouseButtonDown(b mouse.Button, clicks int) {
+	s.input(func(sc Scene) { sc.MouseButtonDown(b, clicks) })
+}
+
+// MouseButtonUp is sent to the top scene.
+func (s *Stack) MouseButtonUp(b mouse.Button, clicks int) {
+	s.input(func(sc Scene) { sc.MouseButtonUp(b, clicks) })
+}
+
+// MouseWheel is sent to the top scene.
+func (s *Stack) MouseWheel(deltaX, deltaY int32) {
+	s.input(func(sc Scene) { sc.MouseWheel(deltaX, deltaY) })
+}
+
+// GamepadConnected is sent to all scenes.
+func (s *Stack) GamepadConnected(pad int) {
+	s.all(func(sc Scene) { sc.GamepadConnected(pad) })
+}
+
+// GamepadDisconnected is sent to all scenes.
+func (s *Stack) GamepadDisconnected(pad int) {
+	s.all(func(sc Scene) { sc.GamepadDisconnected(pad) })
+}
+
+// GamepadButtonDown is sent to the top scene.
+func (s *Stack) GamepadButtonDown(pad int, b gamepad.Button) {
+	s.input(func(sc Scene) { sc.GamepadButtonDown(pad, b) })
+}
+
+// GamepadButtonUp is sent to the top scene.
+func (s *Stack) GamepadButtonUp(pad int, b gamepad.Button) {
+	s.input(func(sc Scene) { sc.GamepadButtonUp(pad, b) })
+}
+
+// GamepadAxisMotion is sent to the top scene.
+func (s *Stack) GamepadAxisMotion(pad int, a gamepad.Axis, value float32) {
+	s.input(func(sc Scene) { sc.GamepadAxisMotion(pad, a, value) })
+}
+
+// TextInput is sent to the top scene.
+func (s *Stack) TextInput(text string) {
+	s.input(func(sc Scene) { sc.TextInput(text) })
+}
+
+// TextEditing is sent to the top scene.
+func (s *Stack) TextEditing(text string, start, length int) {
+	s.input(func(sc Scene) { sc.TextEditing(text, start, length) })
+}
+
+// FileDropped is sent to the top scene.
+func (s *Stack) FileDropped(path string) {
+	s.input(func(sc Scene) { sc.FileDropped(path) })
+}
+
+// TextDropped is sent to the top scene.
+func (s *Stack) TextDropped(text string) {
+	s.input(func(sc Scene) { sc.TextDropped(text) })
+}
+
+//------------------------------------------------------------------------------
+
+// TouchDown is sent to the top scene.
+func (s *Stack) TouchDown(t touch.Touch) {
+	s.input(func(sc Scene) { sc.TouchDown(t) })
+}
+
+// TouchMove is sent to the top scene.
+func (s *Stack) TouchMove(t touch.Touch) {
+	s.input(func(sc Scene) { sc.TouchMove(t) })
+}
+
+// TouchUp is sent to the top scene.
+func (s *Stack) TouchUp(t touch.Touch) {
+	s.input(func(sc Scene) { sc.TouchUp(t) })
+}
+
+// Pinch is sent to the top scene.
+func (s *Stack) Pinch(scale float32, x, y int16) {
+	s.input(func(sc Scene) { sc.Pinch(scale, x, y) })
+}
+
+// Rotate is sent to the top scene.
+func (s *Stack) Rotate(angle float32, x, y int16) {
+	s.input(func(sc Scene) { sc.Rotate(angle, x, y) })
+}
+
+//------------------------------------------------------------------------------
+
+// ScreenResized is sent to all scenes.
+func (s *Stack) ScreenResized(width, height int16, pixel int32) {
+	s.all(func(sc Scene) { sc.ScreenResized(width, height, pixel) })
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,110 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package scene
+
+//------------------------------------------------------------------------------
+
+import (
+	"github.com/drakmaniso/carol"
+	"github.com/drakmaniso/carol/gamepad"
+	"github.com/drakmaniso/carol/key"
+	"github.com/drakmaniso/carol/mouse"
+	"github.com/drakmaniso/carol/touch"
+)
+
+//------------------------------------------------------------------------------
+
+// A Scene handles the callbacks of the game loop while it's on the stack. It
+// has the same methods as carol.GameLoop, except Setup which is replaced by
+// the Enter and Exit hooks.
+//
+// Embed Handlers to implement only the methods needed.
+type Scene interface {
+	// Enter is called when the scene is added to the stack.
+	Enter() error
+	// Exit is called when the scene is removed from the stack.
+	Exit() error
+
+	// Transparent returns true if the scenes below should also be drawn.
+	Transparent() bool
+	// PassThrough returns true if the input events and Update should also be
+	// sent to the scene below.
+	PassThrough() bool
+
+	Update() error
+	Draw(delta float64, lerp float64) error
+
+	WindowShown()
+	WindowHidden()
+	WindowResized(width, height int32)
+	WindowMinimized()
+	WindowMaximized()
+	WindowRestored()
+	WindowMouseEnter()
+	WindowMouseLeave()
+	WindowFocusGained()
+	WindowFocusLost()
+	WindowQuit()
+
+	KeyDown(l key.Label, p key.Position)
+	KeyUp(l key.Label, p key.Position)
+
+	MouseMotion(deltaX, deltaY int32, posX, posY int32)
+	MouseButtonDown(b mouse.Button, clicks int)
+	MouseButtonUp(b mouse.Button, clicks int)
+	MouseWheel(deltaX, deltaY int32)
+
+	GamepadConnected(pad int)
+	GamepadDisconnected(pad int)
+	GamepadButtonDown(pad int, b gamepad.Button)
+	GamepadButtonUp(pad int, b gamepad.Button)
+	GamepadAxisMotion(pad int, a gamepad.Axis, value float32)
+
+	TextInput(text string)
+	TextEditing(text string, start, length int)
+
+	FileDropped(path string)
+	TextDropped(text string)
+
+	TouchDown(t touch.Touch)
+	TouchMove(t touch.Touch)
+	TouchUp(t touch.Touch)
+	Pinch(scale float32, x, y int16)
+	Rotate(angle float32, x, y int16)
+
+	ScreenResized(width, height int16, pixel int32)
+}
+
+//------------------------------------------------------------------------------
+
+// Handlers implements default methods for all the scene callbacks.
+//
+// It's intended to be embedded in user-defined scenes:
+//
+//  type pause struct {
+//    scene.Handlers
+//  }
+type Handlers struct {
+	carol.Handlers
+}
+
+// Enter does nothing.
+func (h Handlers) Enter() error { return nil }
+
+// Exit does nothing.
+func (h Handlers) Exit() error { return nil }
+
+// Transparent returns false.
+func (h Handlers) Transparent() bool { return false }
+
+// PassThrough returns false.
+func (h Handlers) PassThrough() bool { return false }
+
+// Update does nothing.
+func (h Handlers) Update() error { return nil }
+
+// Draw does nothing.
+func (h Handlers) Draw(delta float64, lerp float64) error { return nil }
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,92 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package scene_test
+
+import (
+	"testing"
+
+	"github.com/drakmaniso/carol"
+	"github.com/drakmaniso/carol/key"
+	"github.com/drakmaniso/carol/scene"
+)
+
+//------------------------------------------------------------------------------
+
+type testScene struct {
+	scene.Handlers
+	transparent, pass          bool
+	enters, exits, updates, ks int
+}
+
+func (t *testScene) Enter() error      { t.enters++; return nil }
+func (t *testScene) Exit() error       { t.exits++; return nil }
+func (t *testScene) Transparent() bool { return t.transparent }
+func (t *testScene) PassThrough() bool { return t.pass }
+func (t *testScene) Update() error     { t.updates++; return nil }
+
+func (t *testScene) KeyDown(l key.Label, p key.Position) { t.ks++ }
+
+//------------------------------------------------------------------------------
+
+func TestStack(t *testing.T) {
+	var level, pause, hud testScene
+	pause.transparent = true
+	hud.pass = true
+
+	stack := scene.NewStack(&level)
+	s, err := carol.Simulate(stack, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if level.enters != 1 || stack.Top() != &level {
+		t.Fatalf("first scene not entered during setup")
+	}
+
+	s.Step(10)
+	if level.updates != 10 {
+		t.Errorf("level updated %d times, expected 10", level.updates)
+	}
+
+	// Pause, with a transition of 5 steps
+	stack.Push(&pause, 5*carol.TimeStep())
+	s.Step(10)
+	if _, to, _, ok := stack.Transition(); ok || to != nil {
+		t.Errorf("transition still in progress")
+	}
+	if level.updates != 10 || pause.enters != 1 {
+		t.Errorf("level updated during pause")
+	}
+	if pause.updates != 5 {
+		t.Errorf("pause updated %d times, expected 5", pause.updates)
+	}
+
+	s.Send(carol.Event{Kind: carol.EventKeyDown, Label: key.LabelSpace, Position: key.PositionSpace})
+	s.Step(1)
+	if pause.ks != 1 || level.ks != 0 {
+		t.Errorf("key event not sent only to the top scene")
+	}
+
+	// Back to the level, with a pass-through scene on top
+	stack.Replace(&hud, 0)
+	s.Step(1)
+	if pause.exits != 1 || hud.enters != 1 || stack.Len() != 2 {
+		t.Errorf("replace failed")
+	}
+	s.Send(carol.Event{Kind: carol.EventKeyDown, Label: key.LabelSpace, Position: key.PositionSpace})
+	s.Step(1)
+	if hud.ks != 1 || level.ks != 1 {
+		t.Errorf("key event not passed through")
+	}
+
+	stack.Pop(0)
+	stack.Pop(0)
+	s.Step(1)
+	if stack.Len() != 0 || hud.exits != 1 || level.exits != 1 {
+		t.Errorf("pop failed")
+	}
+}
+
+//------------------------------------------------------------------------------
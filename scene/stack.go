@@ -0,0 +1,228 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package scene
+
+//------------------------------------------------------------------------------
+
+import (
+	"math"
+
+	"github.com/drakmaniso/carol"
+)
+
+//------------------------------------------------------------------------------
+
+// A Stack of scenes. It implements carol.GameLoop.
+type Stack struct {
+	scenes []Scene
+
+	// Pending changes, and the one in progress
+	queue   []carol.State
+	current carol.State
+
+	// Transition in progress
+	from, to    Scene
+	step, steps int
+
+	err error
+}
+
+// NewStack returns a new stack, with first as the initial scene. The Enter
+// hook of first is called during Setup.
+func NewStack(first Scene) *Stack {
+	s := &Stack{}
+	s.Push(first, 0)
+	return s
+}
+
+//------------------------------------------------------------------------------
+
+// Len returns the number of scenes on the stack.
+func (s *Stack) Len() int {
+	return len(s.scenes)
+}
+
+// Top returns the scene at the top of the stack, or nil if it's empty.
+func (s *Stack) Top() Scene {
+	if len(s.scenes) == 0 {
+		return nil
+	}
+	return s.scenes[len(s.scenes)-1]
+}
+
+// Transition returns the scenes involved in the transition in progress, and
+// its progress (between 0 and 1). It returns false if there is no transition.
+//
+// During a transition, neither input events nor Update are sent to the
+// scenes, and both from and to are drawn (from first). Either can be nil
+// when pushing or popping.
+func (s *Stack) Transition() (from, to Scene, progress float64, ok bool) {
+	if s.current == nil || s.steps == 0 {
+		return nil, nil, 0, false
+	}
+	return s.from, s.to, float64(s.step) / float64(s.steps), true
+}
+
+//------------------------------------------------------------------------------
+
+// Push adds a scene on top of the stack, with a transition lasting duration
+// seconds. The Enter hook is called at the start of the transition.
+func (s *Stack) Push(sc Scene, duration float64) {
+	s.schedule(func() carol.State {
+		s.err = sc.Enter()
+		if s.err != nil {
+			return nil
+		}
+		s.from, s.to = nil, sc
+		s.scenes = append(s.scenes, sc)
+		return s.wait(duration, nil)
+	})
+}
+
+// Pop removes the scene at the top of the stack, with a transition lasting
+// duration seconds. The Exit hook is called at the end of the transition.
+func (s *Stack) Pop(duration float64) {
+	s.schedule(func() carol.State {
+		old := s.Top()
+		if old == nil {
+			return nil
+		}
+		s.from, s.to = old, nil
+		return s.wait(duration, func() {
+			s.scenes = s.scenes[:len(s.scenes)-1]
+			s.err = old.Exit()
+		})
+	})
+}
+
+// Replace replaces the scene at the top of the stack, with a transition
+// lasting duration seconds. The Enter hook of the new scene is called at the
+// start of the transition, and the Exit hook of the old one at the end. On an
+// empty stack, it's the same as Push.
+func (s *Stack) Replace(sc Scene, duration float64) {
+	s.schedule(func() carol.State {
+		old := s.Top()
+		s.err = sc.Enter()
+		if s.err != nil {
+			return nil
+		}
+		if old == nil {
+			s.from, s.to = nil, sc
+			s.scenes = append(s.scenes, sc)
+			return s.wait(duration, nil)
+		}
+		s.from, s.to = old, sc
+		return s.wait(duration, func() {
+			s.scenes[len(s.scenes)-1] = sc
+			s.err = old.Exit()
+		})
+	})
+}
+
+//------------------------------------------------------------------------------
+
+func (s *Stack) schedule(change carol.State) {
+	s.queue = append(s.queue, change)
+}
+
+// wait returns a state that lasts for duration seconds (rounded up to a
+// number of Update steps), then calls done (if not nil). The first step is
+// run immediately.
+func (s *Stack) wait(duration float64, done func()) carol.State {
+	s.step, s.steps = 0, int(math.Ceil(duration/carol.TimeStep()-1e-6))
+	var w carol.State
+	w = func() carol.State {
+		if s.step < s.steps {
+			s.step++
+			return w
+		}
+		if done != nil {
+			done()
+		}
+		s.from, s.to = nil, nil
+		return nil
+	}
+	return w()
+}
+
+// replacing returns true during the transition of a Replace.
+func (s *Stack) replacing() bool {
+	return s.current != nil && s.from != nil && s.to != nil
+}
+
+// advance runs the pending changes, until one of them needs more time.
+func (s *Stack) advance() error {
+	for s.err == nil {
+		if s.current == nil {
+			if len(s.queue) == 0 {
+				break
+			}
+			s.current = s.queue[0]
+			s.queue = s.queue[1:]
+		}
+		s.current.Update()
+		if s.current != nil {
+			break
+		}
+	}
+	err := s.err
+	s.err = nil
+	return err
+}
+
+//------------------------------------------------------------------------------
+
+// Setup applies the initial changes (i.e. enters the first scene).
+func (s *Stack) Setup() error {
+	return s.advance()
+}
+
+// Update applies the pending changes, then calls Update on the top scene (and
+// the scenes below, if it passes through). Nothing is updated during a
+// transition.
+func (s *Stack) Update() error {
+	err := s.advance()
+	if err != nil {
+		return err
+	}
+	if s.current != nil {
+		return nil
+	}
+	for i := len(s.scenes) - 1; i >= 0; i-- {
+		err := s.scenes[i].Update()
+		if err != nil {
+			return err
+		}
+		if !s.scenes[i].PassThrough() {
+			break
+		}
+	}
+	return nil
+}
+
+// Draw calls Draw on the top scene, preceded by the scenes below it if it's
+// transparent. During a transition, the scenes being replaced or popped are
+// drawn too.
+func (s *Stack) Draw(delta float64, lerp float64) error {
+	first := len(s.scenes) - 1
+	for first > 0 && s.scenes[first].Transparent() {
+		first--
+	}
+	if first < 0 {
+		first = 0
+	}
+	for _, sc := range s.scenes[first:] {
+		err := sc.Draw(delta, lerp)
+		if err != nil {
+			return err
+		}
+	}
+	if s.replacing() {
+		// The new scene is not yet on the stack
+		return s.to.Draw(delta, lerp)
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
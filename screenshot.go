@@ -0,0 +1,115 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package carol
+
+//------------------------------------------------------------------------------
+
+import (
+	"errors"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/drakmaniso/carol/internal"
+	"github.com/drakmaniso/carol/x/gl"
+)
+
+//------------------------------------------------------------------------------
+
+func init() {
+	internal.SaveScreenshot = func() {
+		SaveScreenshot(func(p string, err error) {
+			if err != nil {
+				internal.ErrorLog.Printf("%s", err)
+				return
+			}
+			internal.Log.Printf("Screenshot saved to %s", p)
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// screenshotRequests holds the WindowScreenshot callbacks waiting for the end
+// of the frame.
+var screenshotRequests []func(*image.NRGBA, error)
+
+// WindowScreenshot captures the content of the window at the end of the
+// current frame (i.e. with the virtual screen scaled to the window), just
+// before it is displayed, and passes it to done.
+func WindowScreenshot(done func(m *image.NRGBA, err error)) {
+	if internal.Headless || internal.Offscreen {
+		done(nil, errors.New("no window to capture"))
+		return
+	}
+	screenshotRequests = append(screenshotRequests, done)
+}
+
+// captureWindow serves the pending WindowScreenshot requests. It must be
+// called after drawing the frame, and before swapping the window buffers (the
+// content of the front buffer is undefined).
+func captureWindow() {
+	rr := screenshotRequests
+	screenshotRequests = nil
+	for _, r := range rr {
+		r(readWindow())
+	}
+}
+
+func readWindow() (*image.NRGBA, error) {
+	w, h := int(internal.Window.Width), int(internal.Window.Height)
+	buf := make([]uint8, 4*w*h)
+	gl.DefaultFramebuffer.ReadPixels(gl.BackBuffer, 0, 0, int32(w), int32(h), buf)
+	err := gl.Err()
+	if err != nil {
+		return nil, Error("in window screenshot", err)
+	}
+
+	m := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		// OpenGL rows are bottom to top
+		copy(m.Pix[m.Stride*y:m.Stride*(y+1)], buf[4*w*(h-1-y):])
+	}
+	for i := 3; i < len(m.Pix); i += 4 {
+		m.Pix[i] = 0xFF
+	}
+	return m, nil
+}
+
+// SaveScreenshot saves the content of the window as a PNG file, in the
+// directory of the executable. The capture is made at the end of the current
+// frame (see WindowScreenshot). The name of the file contains the current date
+// and time; done, if not nil, is called with its path.
+//
+// In debug mode, the default handlers call it when F12 is pressed.
+func SaveScreenshot(done func(path string, err error)) {
+	WindowScreenshot(func(m *image.NRGBA, err error) {
+		p := ""
+		if err == nil {
+			p, err = saveScreenshot(m)
+		}
+		if done != nil {
+			done(p, err)
+		}
+	})
+}
+
+func saveScreenshot(m *image.NRGBA) (string, error) {
+	n := "screenshot-" + time.Now().Format("20060102-150405.000") + ".png"
+	p := filepath.Join(internal.FilePath, n)
+	f, err := os.Create(p)
+	if err != nil {
+		return "", Error("in screenshot", err)
+	}
+	err = png.Encode(f, m)
+	if err != nil {
+		f.Close()
+		return "", Error("in screenshot encoding", err)
+	}
+	return p, Error("in screenshot", f.Close())
+}
+
+//------------------------------------------------------------------------------
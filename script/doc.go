@@ -0,0 +1,22 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+/*
+Package script runs game scripts (e.g. cutscenes or enemy patterns) written as
+sequential code:
+
+  script.Start("intro", func(t *script.Task) error {
+    door.Open()
+    t.WaitSeconds(1.5)
+    hero.WalkTo(door.Position)
+    t.WaitUntil(hero.Arrived)
+    return dialog.Say("Hello!")
+  })
+
+Each task runs in its own goroutine, but only one of them runs at any time,
+and never concurrently with the game loop: the tasks are resumed one after
+the other, just before each Update step, and run until they wait again.
+
+If a task returns an error (or panics), the game loop stops with that error.
+*/
+package script
@@ -0,0 +1,131 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package script_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/drakmaniso/carol"
+	"github.com/drakmaniso/carol/script"
+)
+
+//------------------------------------------------------------------------------
+
+type loop struct {
+	carol.Handlers
+	updates int
+}
+
+func (l *loop) Setup() error            { return nil }
+func (l *loop) Update() error           { l.updates++; return nil }
+func (l *loop) Draw(_, _ float64) error { return nil }
+
+//------------------------------------------------------------------------------
+
+func TestTasks(t *testing.T) {
+	var l loop
+	s, err := carol.Simulate(&l, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	var trace []int
+	open := false
+	script.Start("sequence", func(t *script.Task) error {
+		trace = append(trace, l.updates)
+		t.Wait(5)
+		trace = append(trace, l.updates)
+		t.WaitUntil(func() bool { return open })
+		trace = append(trace, l.updates)
+		return nil
+	})
+	forever := script.Start("forever", func(t *script.Task) error {
+		for {
+			t.Yield()
+		}
+	})
+
+	s.Step(10)
+	open = true
+	s.Step(1)
+	if len(trace) != 3 || trace[0] != 0 || trace[1] != 5 || trace[2] != 10 {
+		t.Errorf("unexpected trace %v", trace)
+	}
+
+	forever.Cancel()
+	s.Step(1)
+	if forever.IsRunning() || forever.Err() != nil {
+		t.Errorf("task not cancelled")
+	}
+
+	script.Start("failing", func(t *script.Task) error {
+		t.Yield()
+		return errors.New("oops")
+	})
+	err = s.Step(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.Step(1)
+	if err == nil {
+		t.Errorf("task error not reported")
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestQuit(t *testing.T) {
+	var l loop
+	s, err := carol.Simulate(&l, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unwound, resumed := false, 0
+	task := script.Start("waiting", func(t *script.Task) error {
+		defer func() { unwound = true }()
+		for {
+			t.Yield()
+			resumed++
+		}
+	})
+	s.Step(2)
+	s.Close()
+	if !unwound || task.IsRunning() {
+		t.Errorf("task not unwound by Close")
+	}
+
+	s, err = carol.Simulate(&l, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	s.Step(5)
+	if resumed != 1 {
+		t.Errorf("task resumed %d times, expected 1", resumed)
+	}
+}
+
+func TestPanic(t *testing.T) {
+	var l loop
+	s, err := carol.Simulate(&l, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	script.Start("panicking", func(t *script.Task) error {
+		var m map[string]int
+		m["boom"]++
+		return nil
+	})
+	err = s.Step(1)
+	if err == nil || !strings.Contains(err.Error(), "script_test.go") {
+		t.Errorf("got %v, expected an error with the stack trace", err)
+	}
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,231 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package script
+
+//------------------------------------------------------------------------------
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"runtime/debug"
+
+	"github.com/drakmaniso/carol"
+	"github.com/drakmaniso/carol/internal"
+)
+
+//------------------------------------------------------------------------------
+
+func init() {
+	internal.ScriptStep = step
+	internal.ScriptQuit = quit
+}
+
+//------------------------------------------------------------------------------
+
+// A Task is a running script.
+type Task struct {
+	name string
+	f    func(t *Task) error
+
+	started   bool
+	done      bool
+	cancelled bool
+	err       error
+
+	// Waiting condition
+	steps int
+	until func() bool
+
+	resume chan struct{}
+	yield  chan struct{}
+}
+
+var tasks []*Task
+
+// errCancelled is used to unwind the goroutine of a cancelled task.
+var errCancelled = errors.New("task cancelled")
+
+//------------------------------------------------------------------------------
+
+// Start creates a new task running f. The task starts just before the next
+// Update step.
+func Start(name string, f func(t *Task) error) *Task {
+	t := &Task{
+		name:   name,
+		f:      f,
+		resume: make(chan struct{}),
+		yield:  make(chan struct{}),
+	}
+	tasks = append(tasks, t)
+	return t
+}
+
+// CancelAll cancels all tasks.
+func CancelAll() {
+	for _, t := range tasks {
+		t.Cancel()
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// Name returns the name of the task.
+func (t *Task) Name() string {
+	return t.name
+}
+
+// Cancel stops the task. If called by another task or by the game, the task
+// is stopped during the next step; if called by the task itself, it's stopped
+// at its next wait.
+func (t *Task) Cancel() {
+	t.cancelled = true
+}
+
+// IsRunning returns true if the task is neither finished nor cancelled.
+func (t *Task) IsRunning() bool {
+	return !t.done && !t.cancelled
+}
+
+// Err returns the error returned by the task, if it has finished.
+func (t *Task) Err() error {
+	return t.err
+}
+
+//------------------------------------------------------------------------------
+
+// Yield suspends the task until the next Update step.
+func (t *Task) Yield() {
+	t.Wait(1)
+}
+
+// Wait suspends the task for a number of Update steps.
+func (t *Task) Wait(steps int) {
+	if steps < 1 {
+		steps = 1
+	}
+	t.steps = steps
+	t.suspend()
+}
+
+// WaitSeconds suspends the task for a duration, rounded up to a whole number
+// of Update steps.
+func (t *Task) WaitSeconds(seconds float64) {
+	t.Wait(int(math.Ceil(seconds/carol.TimeStep() - 1e-6)))
+}
+
+// WaitUntil suspends the task until cond returns true. The condition is
+// checked before each Update step, starting with the next one.
+func (t *Task) WaitUntil(cond func() bool) {
+	t.until = cond
+	t.suspend()
+}
+
+// suspend gives control back to the runner, and waits to be resumed.
+func (t *Task) suspend() {
+	if t.cancelled {
+		panic(errCancelled)
+	}
+	t.yield <- struct{}{}
+	<-t.resume
+	if t.cancelled {
+		panic(errCancelled)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// run is the body of the task goroutine.
+func (t *Task) run() {
+	defer func() {
+		if r := recover(); r != nil && r != errCancelled {
+			t.err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+		t.done = true
+		t.yield <- struct{}{}
+	}()
+	t.err = t.f(t)
+}
+
+// ready returns true if the task should be resumed during this step.
+func (t *Task) ready() bool {
+	if t.cancelled {
+		return true
+	}
+	if t.steps > 0 {
+		t.steps--
+		return t.steps == 0
+	}
+	if t.until != nil {
+		if !t.until() {
+			return false
+		}
+		t.until = nil
+	}
+	return true
+}
+
+//------------------------------------------------------------------------------
+
+// step resumes, one at a time, all the tasks ready to continue.
+func step() error {
+	var err error
+	n := len(tasks) // tasks started during this step wait for the next one
+	for i := 0; i < n && err == nil; i++ {
+		t := tasks[i]
+		if t.done || !t.ready() {
+			continue
+		}
+		switch {
+		case !t.started && t.cancelled:
+			t.done = true
+			continue
+		case !t.started:
+			t.started = true
+			go t.run()
+		default:
+			t.resume <- struct{}{}
+		}
+		<-t.yield
+		if t.done && t.err != nil {
+			err = carol.Error(`in script "`+t.name+`"`, t.err)
+		}
+	}
+
+	// Remove finished tasks
+	k := 0
+	for _, t := range tasks {
+		if !t.done {
+			tasks[k] = t
+			k++
+		}
+	}
+	for i := k; i < len(tasks); i++ {
+		tasks[i] = nil
+	}
+	tasks = tasks[:k]
+
+	return err
+}
+
+//------------------------------------------------------------------------------
+
+// quit is called when the game loop ends: all tasks are cancelled, and their
+// goroutines unwound.
+func quit() {
+	for _, t := range tasks {
+		t.cancelled = true
+		if t.started && !t.done {
+			t.resume <- struct{}{}
+			<-t.yield
+		}
+		t.done = true
+	}
+	for i := range tasks {
+		tasks[i] = nil
+	}
+	tasks = tasks[:0]
+}
+
+//------------------------------------------------------------------------------
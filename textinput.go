@@ -0,0 +1,39 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package carol
+
+//------------------------------------------------------------------------------
+
+import (
+	"github.com/drakmaniso/carol/internal"
+)
+
+//------------------------------------------------------------------------------
+
+// StartTextInput enables the TextInput and TextEditing events, e.g. when a
+// text field gains focus. The rectangle (in window coordinates) is where the
+// text is being typed: it's used as a hint to place the IME candidate list.
+//
+// Text input is disabled when the game starts.
+func StartTextInput(x, y, width, height int32) {
+	internal.TextInputStart(x, y, width, height)
+}
+
+// SetTextInputRect changes the rectangle hint given to StartTextInput, e.g.
+// when the cursor moves.
+func SetTextInputRect(x, y, width, height int32) {
+	internal.TextInputSetRect(x, y, width, height)
+}
+
+// StopTextInput disables the TextInput and TextEditing events.
+func StopTextInput() {
+	internal.TextInputStop()
+}
+
+// IsTextInputActive returns true if text input is enabled.
+func IsTextInputActive() bool {
+	return internal.TextInputActive()
+}
+
+//------------------------------------------------------------------------------
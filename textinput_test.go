@@ -0,0 +1,66 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package carol_test
+
+import (
+	"testing"
+
+	"github.com/drakmaniso/carol"
+)
+
+//------------------------------------------------------------------------------
+
+type textLoop struct {
+	carol.Handlers
+	text    string
+	editing string
+	start   int
+	length  int
+}
+
+func (l *textLoop) Setup() error            { return nil }
+func (l *textLoop) Update() error           { return nil }
+func (l *textLoop) Draw(_, _ float64) error { return nil }
+func (l *textLoop) TextInput(t string)      { l.text += t }
+
+func (l *textLoop) TextEditing(t string, start, length int) {
+	l.editing, l.start, l.length = t, start, length
+}
+
+//------------------------------------------------------------------------------
+
+func TestTextInput(t *testing.T) {
+	ts := carol.TimeStep()
+	script := carol.Script{
+		{Kind: carol.EventTextInput, Time: ts, Text: "a"},
+		{Kind: carol.EventTextEditing, Time: 2 * ts, Text: "にほ", Start: 2, Length: 0},
+		{Kind: carol.EventTextEditing, Time: 3 * ts, Text: "", Start: 0, Length: 0},
+		{Kind: carol.EventTextInput, Time: 3 * ts, Text: "日本"},
+	}
+
+	var l textLoop
+	s, err := carol.Simulate(&l, &script, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	err = s.Step(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.text != "a" || l.editing != "にほ" || l.start != 2 {
+		t.Errorf("got text %q, composition %q at %d", l.text, l.editing, l.start)
+	}
+
+	err = s.Step(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.text != "a日本" || l.editing != "" {
+		t.Errorf("got text %q, composition %q", l.text, l.editing)
+	}
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,15 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+/*
+Package touch provides touch screen support.
+
+Each finger on the screen is identified by an ID, valid until the finger is
+lifted. Positions are given both normalized (from 0 to 1, relative to the
+window) and in screen pixels.
+
+The game loop also receives the Pinch and Rotate gestures, recognized from the
+first two fingers on the screen. By default, the first finger is also reported
+as the left mouse button (see carol.TouchMouse).
+*/
+package touch
@@ -0,0 +1,43 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package touch
+
+//------------------------------------------------------------------------------
+
+import (
+	"github.com/drakmaniso/carol/internal"
+)
+
+//------------------------------------------------------------------------------
+
+// A Touch describes a finger on the screen.
+type Touch = internal.Touch
+
+//------------------------------------------------------------------------------
+
+// Fingers returns all the fingers currently on the screen, in order of
+// arrival.
+func Fingers() []Touch {
+	t := make([]Touch, len(internal.Touches))
+	copy(t, internal.Touches)
+	return t
+}
+
+// Count returns the number of fingers on the screen.
+func Count() int {
+	return len(internal.Touches)
+}
+
+// Finger returns the current state of a finger. It returns false if the
+// finger isn't touching the screen.
+func Finger(id int64) (Touch, bool) {
+	for _, t := range internal.Touches {
+		if t.Finger == id {
+			return t, true
+		}
+	}
+	return Touch{}, false
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,81 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package carol_test
+
+import (
+	"testing"
+
+	"github.com/drakmaniso/carol"
+	"github.com/drakmaniso/carol/mouse"
+	"github.com/drakmaniso/carol/touch"
+)
+
+//------------------------------------------------------------------------------
+
+type touchLoop struct {
+	carol.Handlers
+	downs, ups, clicks int
+	pinch, rotate      float32
+}
+
+func (l *touchLoop) Setup() error                 { return nil }
+func (l *touchLoop) Update() error                { return nil }
+func (l *touchLoop) Draw(_, _ float64) error      { return nil }
+func (l *touchLoop) TouchDown(t touch.Touch)      { l.downs++ }
+func (l *touchLoop) TouchUp(t touch.Touch)        { l.ups++ }
+func (l *touchLoop) Pinch(s float32, _, _ int16)  { l.pinch *= s }
+func (l *touchLoop) Rotate(a float32, _, _ int16) { l.rotate += a }
+
+func (l *touchLoop) MouseButtonDown(b mouse.Button, _ int) {
+	if b == mouse.Left {
+		l.clicks++
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestTouch(t *testing.T) {
+	script := carol.Script{
+		{Kind: carol.EventTouchDown, Time: 0.01, Finger: 1, TouchX: 0.4, TouchY: 0.5},
+		{Kind: carol.EventTouchDown, Time: 0.02, Finger: 2, TouchX: 0.6, TouchY: 0.5},
+		{Kind: carol.EventTouchMove, Time: 0.03, Finger: 2, TouchX: 0.61, TouchY: 0.5},
+		{Kind: carol.EventTouchMove, Time: 0.04, Finger: 2, TouchX: 0.7, TouchY: 0.5},
+		{Kind: carol.EventTouchUp, Time: 0.08, Finger: 2, TouchX: 0.7, TouchY: 0.5},
+		{Kind: carol.EventTouchUp, Time: 0.09, Finger: 1, TouchX: 0.4, TouchY: 0.5},
+	}
+
+	l := touchLoop{pinch: 1}
+	s, err := carol.Simulate(&l, &script, false, carol.Window(1000, 500))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	err = s.Step(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.downs != 2 || l.ups != 0 || touch.Count() != 2 {
+		t.Errorf("got %d downs, %d ups and %d fingers", l.downs, l.ups, touch.Count())
+	}
+	if l.pinch < 1.49 || l.pinch > 1.51 {
+		t.Errorf("got pinch %f, expected 1.5", l.pinch)
+	}
+	if l.rotate != 0 {
+		t.Errorf("got rotation %f, expected 0", l.rotate)
+	}
+	if l.clicks != 1 || !mouse.IsPressed(mouse.Left) {
+		t.Errorf("got %d mouse clicks, expected 1", l.clicks)
+	}
+
+	err = s.Step(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.ups != 2 || touch.Count() != 0 || mouse.IsPressed(mouse.Left) {
+		t.Errorf("got %d ups and %d fingers", l.ups, touch.Count())
+	}
+}
+
+//------------------------------------------------------------------------------
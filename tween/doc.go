@@ -0,0 +1,24 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+/*
+Package tween provides timers and tweens, advanced by the fixed time step of
+the game loop.
+
+Timers call a function after a delay, or repeatedly:
+
+  tween.After(2, func() { door.Open() })
+  blink := tween.Every(0.5, func() { cursor.Visible = !cursor.Visible })
+  ...
+  blink.Cancel()
+
+Tweens animate a value from its current state to a target:
+
+  tween.Plane(&player.Position, target, 0.3, tween.OutCubic)
+  tween.Colour(&background, colour.RGBA{0, 0, 0, 1}, 1, tween.Linear)
+
+Everything is advanced once per Update step (just before the Update callback),
+by carol.TimeStep() seconds. Animations are thus independent of the frame
+rate, and replayed identically.
+*/
+package tween
@@ -0,0 +1,155 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package tween
+
+//------------------------------------------------------------------------------
+
+import (
+	"math"
+
+	"github.com/drakmaniso/carol/x/math32"
+)
+
+//------------------------------------------------------------------------------
+
+// An Easing function maps the linear progress of a tween (between 0 and 1) to
+// the actual progress. It must return 0 for 0 and 1 for 1, but may overshoot
+// in between.
+type Easing func(t float32) float32
+
+// Linear progress.
+func Linear(t float32) float32 {
+	return t
+}
+
+//------------------------------------------------------------------------------
+
+// out returns the reversed easing of e (i.e. starting fast).
+func out(e Easing, t float32) float32 {
+	return 1 - e(1-t)
+}
+
+// inOut returns the symmetric easing of e (i.e. slow at both ends).
+func inOut(e Easing, t float32) float32 {
+	if t < 0.5 {
+		return e(2*t) / 2
+	}
+	return 1 - e(2-2*t)/2
+}
+
+//------------------------------------------------------------------------------
+
+// InQuad is a quadratic easing, starting slowly.
+func InQuad(t float32) float32 { return t * t }
+
+// OutQuad is a quadratic easing, ending slowly.
+func OutQuad(t float32) float32 { return out(InQuad, t) }
+
+// InOutQuad is a quadratic easing, starting and ending slowly.
+func InOutQuad(t float32) float32 { return inOut(InQuad, t) }
+
+// InCubic is a cubic easing, starting slowly.
+func InCubic(t float32) float32 { return t * t * t }
+
+// OutCubic is a cubic easing, ending slowly.
+func OutCubic(t float32) float32 { return out(InCubic, t) }
+
+// InOutCubic is a cubic easing, starting and ending slowly.
+func InOutCubic(t float32) float32 { return inOut(InCubic, t) }
+
+// InQuart is a quartic easing, starting slowly.
+func InQuart(t float32) float32 { return t * t * t * t }
+
+// OutQuart is a quartic easing, ending slowly.
+func OutQuart(t float32) float32 { return out(InQuart, t) }
+
+// InOutQuart is a quartic easing, starting and ending slowly.
+func InOutQuart(t float32) float32 { return inOut(InQuart, t) }
+
+// InSine is a sinusoidal easing, starting slowly.
+func InSine(t float32) float32 { return 1 - math32.Cos(t*math32.Pi/2) }
+
+// OutSine is a sinusoidal easing, ending slowly.
+func OutSine(t float32) float32 { return math32.Sin(t * math32.Pi / 2) }
+
+// InOutSine is a sinusoidal easing, starting and ending slowly.
+func InOutSine(t float32) float32 { return (1 - math32.Cos(t*math32.Pi)) / 2 }
+
+// InExpo is an exponential easing, starting slowly.
+func InExpo(t float32) float32 {
+	if t <= 0 {
+		return 0
+	}
+	return float32(math.Pow(2, float64(10*t-10)))
+}
+
+// OutExpo is an exponential easing, ending slowly.
+func OutExpo(t float32) float32 { return out(InExpo, t) }
+
+// InOutExpo is an exponential easing, starting and ending slowly.
+func InOutExpo(t float32) float32 { return inOut(InExpo, t) }
+
+// InCirc is a circular easing, starting slowly.
+func InCirc(t float32) float32 { return 1 - math32.Sqrt(1-t*t) }
+
+// OutCirc is a circular easing, ending slowly.
+func OutCirc(t float32) float32 { return out(InCirc, t) }
+
+// InOutCirc is a circular easing, starting and ending slowly.
+func InOutCirc(t float32) float32 { return inOut(InCirc, t) }
+
+//------------------------------------------------------------------------------
+
+const backOvershoot = 1.70158
+
+// InBack is an easing that goes slightly backward before starting.
+func InBack(t float32) float32 {
+	return t * t * ((backOvershoot+1)*t - backOvershoot)
+}
+
+// OutBack is an easing that overshoots the target before settling.
+func OutBack(t float32) float32 { return out(InBack, t) }
+
+// InOutBack combines InBack and OutBack.
+func InOutBack(t float32) float32 { return inOut(InBack, t) }
+
+// InElastic is an easing that oscillates with increasing amplitude.
+func InElastic(t float32) float32 {
+	if t <= 0 || t >= 1 {
+		return t
+	}
+	return -float32(math.Pow(2, float64(10*t-10))) * math32.Sin((10*t-10.75)*(2*math32.Pi/3))
+}
+
+// OutElastic is an easing that oscillates around the target before settling.
+func OutElastic(t float32) float32 { return out(InElastic, t) }
+
+// InOutElastic combines InElastic and OutElastic.
+func InOutElastic(t float32) float32 { return inOut(InElastic, t) }
+
+// OutBounce is an easing that bounces on the target.
+func OutBounce(t float32) float32 {
+	const n, d = 7.5625, 2.75
+	switch {
+	case t < 1/d:
+		return n * t * t
+	case t < 2/d:
+		t -= 1.5 / d
+		return n*t*t + 0.75
+	case t < 2.5/d:
+		t -= 2.25 / d
+		return n*t*t + 0.9375
+	default:
+		t -= 2.625 / d
+		return n*t*t + 0.984375
+	}
+}
+
+// InBounce is an easing that bounces on the start value.
+func InBounce(t float32) float32 { return out(OutBounce, t) }
+
+// InOutBounce combines InBounce and OutBounce.
+func InOutBounce(t float32) float32 { return inOut(InBounce, t) }
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,154 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package tween
+
+//------------------------------------------------------------------------------
+
+import (
+	"github.com/drakmaniso/carol"
+	"github.com/drakmaniso/carol/internal"
+)
+
+//------------------------------------------------------------------------------
+
+func init() {
+	internal.TweenStep = step
+	internal.TweenQuit = quit
+}
+
+//------------------------------------------------------------------------------
+
+// A job is either a timer or a tween.
+type job struct {
+	elapsed  float64
+	duration float64
+	repeat   bool
+	update   func(t float64) // called at each step with the elapsed time
+	done     func()
+	finished bool
+}
+
+var jobs []*job
+
+// A Handle designates a running timer or tween.
+type Handle struct {
+	j *job
+}
+
+//------------------------------------------------------------------------------
+
+// start adds a job; it will be advanced starting with the next Update step.
+func start(j *job) Handle {
+	jobs = append(jobs, j)
+	return Handle{j: j}
+}
+
+// After calls f once, after delay seconds.
+func After(delay float64, f func()) Handle {
+	return start(&job{
+		duration: delay,
+		done:     f,
+	})
+}
+
+// Every calls f repeatedly, every period seconds, until cancelled.
+func Every(period float64, f func()) Handle {
+	return start(&job{
+		duration: period,
+		repeat:   true,
+		done:     f,
+	})
+}
+
+//------------------------------------------------------------------------------
+
+// Cancel stops the timer or tween. Its function is not called, and a tweened
+// value keeps its current state. It's safe to cancel a handle more than once,
+// or a zero handle.
+func (h Handle) Cancel() {
+	if h.j != nil {
+		h.j.finished = true
+	}
+}
+
+// Then sets a function to be called when the tween is completed (not when it
+// is cancelled). It replaces the function of a timer.
+func (h Handle) Then(f func()) Handle {
+	if h.j != nil {
+		h.j.done = f
+	}
+	return h
+}
+
+// IsActive returns true if the timer or tween is neither completed nor
+// cancelled.
+func (h Handle) IsActive() bool {
+	return h.j != nil && !h.j.finished
+}
+
+// Clear cancels all timers and tweens.
+func Clear() {
+	for _, j := range jobs {
+		j.finished = true
+	}
+}
+
+// quit is called when the game loop ends.
+func quit() {
+	Clear()
+	for i := range jobs {
+		jobs[i] = nil
+	}
+	jobs = jobs[:0]
+}
+
+//------------------------------------------------------------------------------
+
+// step advances all jobs by one time step.
+func step() {
+	dt := carol.TimeStep()
+	n := len(jobs) // jobs started during this step wait for the next one
+	for i := 0; i < n; i++ {
+		j := jobs[i]
+		if j.finished {
+			continue
+		}
+		j.elapsed += dt
+		// Tolerate rounding errors in the sum of time steps
+		over := j.elapsed >= j.duration-dt/1024
+		if j.update != nil {
+			if over {
+				j.update(j.duration)
+			} else {
+				j.update(j.elapsed)
+			}
+		}
+		if !over {
+			continue
+		}
+		if j.repeat {
+			j.elapsed -= j.duration
+		} else {
+			j.finished = true
+		}
+		if j.done != nil {
+			j.done()
+		}
+	}
+
+	// Remove finished jobs
+	k := 0
+	for _, j := range jobs {
+		if !j.finished {
+			jobs[k] = j
+			k++
+		}
+	}
+	for i := k; i < len(jobs); i++ {
+		jobs[i] = nil
+	}
+	jobs = jobs[:k]
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,83 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package tween
+
+//------------------------------------------------------------------------------
+
+import (
+	"github.com/drakmaniso/carol/colour"
+	"github.com/drakmaniso/carol/plane"
+	"github.com/drakmaniso/carol/space"
+)
+
+//------------------------------------------------------------------------------
+
+// progress returns the eased progress of a tween at elapsed time t.
+func progress(t, duration float64, e Easing) float32 {
+	if duration <= 0 {
+		return 1
+	}
+	return e(float32(t / duration))
+}
+
+//------------------------------------------------------------------------------
+
+// Float animates the value pointed by v from its current state to a target,
+// over duration seconds.
+func Float(v *float32, to float32, duration float64, e Easing) Handle {
+	from := *v
+	return start(&job{
+		duration: duration,
+		update: func(t float64) {
+			p := progress(t, duration, e)
+			*v = from + (to-from)*p
+		},
+	})
+}
+
+// Plane animates the coordinates pointed by v from their current state to a
+// target, over duration seconds.
+func Plane(v *plane.Coord, to plane.Coord, duration float64, e Easing) Handle {
+	from := *v
+	return start(&job{
+		duration: duration,
+		update: func(t float64) {
+			p := progress(t, duration, e)
+			*v = from.Plus(to.Minus(from).Times(p))
+		},
+	})
+}
+
+// Space animates the coordinates pointed by v from their current state to a
+// target, over duration seconds.
+func Space(v *space.Coord, to space.Coord, duration float64, e Easing) Handle {
+	from := *v
+	return start(&job{
+		duration: duration,
+		update: func(t float64) {
+			p := progress(t, duration, e)
+			*v = from.Plus(to.Minus(from).Times(p))
+		},
+	})
+}
+
+// Colour animates the colour pointed by v from its current state to a target,
+// over duration seconds. The interpolation is done in linear color space.
+func Colour(v *colour.RGBA, to colour.RGBA, duration float64, e Easing) Handle {
+	from := *v
+	return start(&job{
+		duration: duration,
+		update: func(t float64) {
+			p := progress(t, duration, e)
+			*v = colour.RGBA{
+				R: from.R + (to.R-from.R)*p,
+				G: from.G + (to.G-from.G)*p,
+				B: from.B + (to.B-from.B)*p,
+				A: from.A + (to.A-from.A)*p,
+			}
+		},
+	})
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,106 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package tween_test
+
+import (
+	"testing"
+
+	"github.com/drakmaniso/carol"
+	"github.com/drakmaniso/carol/tween"
+	"github.com/drakmaniso/carol/x/math32"
+)
+
+//------------------------------------------------------------------------------
+
+func TestEasings(t *testing.T) {
+	easings := map[string]tween.Easing{
+		"Linear": tween.Linear,
+		"InQuad": tween.InQuad, "OutQuad": tween.OutQuad, "InOutQuad": tween.InOutQuad,
+		"InCubic": tween.InCubic, "OutCubic": tween.OutCubic, "InOutCubic": tween.InOutCubic,
+		"InSine": tween.InSine, "OutSine": tween.OutSine, "InOutSine": tween.InOutSine,
+		"InExpo": tween.InExpo, "OutExpo": tween.OutExpo, "InOutExpo": tween.InOutExpo,
+		"InCirc": tween.InCirc, "OutCirc": tween.OutCirc, "InOutCirc": tween.InOutCirc,
+		"InBack": tween.InBack, "OutBack": tween.OutBack, "InOutBack": tween.InOutBack,
+		"InElastic": tween.InElastic, "OutElastic": tween.OutElastic, "InOutElastic": tween.InOutElastic,
+		"InBounce": tween.InBounce, "OutBounce": tween.OutBounce, "InOutBounce": tween.InOutBounce,
+	}
+	for n, e := range easings {
+		if v := e(0); !math32.IsNearlyEqual(v, 0, 1e-5) {
+			t.Errorf("%s(0) = %v", n, v)
+		}
+		if v := e(1); !math32.IsNearlyEqual(v, 1, 1e-5) {
+			t.Errorf("%s(1) = %v", n, v)
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type loop struct {
+	carol.Handlers
+}
+
+func (l loop) Setup() error            { return nil }
+func (l loop) Update() error           { return nil }
+func (l loop) Draw(_, _ float64) error { return nil }
+
+func TestTimers(t *testing.T) {
+	s, err := carol.Simulate(loop{}, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	ts := carol.TimeStep()
+	after, every := 0, 0
+	tween.After(10*ts, func() { after++ })
+	h := tween.Every(4*ts, func() { every++ })
+	var v float32
+	tw := tween.Float(&v, 10, 20*ts, tween.Linear)
+
+	s.Step(10)
+	if after != 1 || every != 2 {
+		t.Errorf("after 10 steps, got %d and %d calls, expected 1 and 2", after, every)
+	}
+	if !math32.IsNearlyEqual(v, 5, 1e-4) {
+		t.Errorf("tween at half time: %v, expected 5", v)
+	}
+
+	h.Cancel()
+	s.Step(20)
+	if after != 1 || every != 2 {
+		t.Errorf("after 30 steps, got %d and %d calls, expected 1 and 2", after, every)
+	}
+	if v != 10 || tw.IsActive() {
+		t.Errorf("tween not completed: %v", v)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func TestQuit(t *testing.T) {
+	s, err := carol.Simulate(loop{}, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := 0
+	h := tween.Every(carol.TimeStep(), func() { calls++ })
+	s.Step(1)
+	s.Close()
+	if h.IsActive() {
+		t.Errorf("timer still active after Close")
+	}
+
+	s, err = carol.Simulate(loop{}, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	s.Step(5)
+	if calls != 1 {
+		t.Errorf("got %d calls, expected 1", calls)
+	}
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,31 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package carol
+
+//------------------------------------------------------------------------------
+
+import (
+	"github.com/drakmaniso/carol/internal"
+)
+
+//------------------------------------------------------------------------------
+
+// UserDataDir returns the per-user directory where the game should write its
+// data (e.g. saved games), and creates it if needed. It is named after the
+// Title option, inside:
+//
+// - $XDG_DATA_HOME (or ~/.local/share) on Linux and other unix systems;
+//
+// - %AppData% on Windows;
+//
+// - ~/Library/Application Support on macOS.
+func UserDataDir() (string, error) {
+	d, err := internal.UserDataDir()
+	if err != nil {
+		return "", internal.Error("while creating user data directory", err)
+	}
+	return d, nil
+}
+
+//------------------------------------------------------------------------------
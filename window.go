@@ -6,6 +6,8 @@ package carol
 //------------------------------------------------------------------------------
 
 import (
+	"image"
+
 	"github.com/drakmaniso/carol/internal"
 )
 
@@ -27,3 +29,97 @@ func WindowSize() (width, height int32) {
 }
 
 //------------------------------------------------------------------------------
+
+// SetWindowTitle changes the title of the window.
+func SetWindowTitle(title string) {
+	internal.WindowSetTitle(title)
+}
+
+// SetWindowSize changes the size of the window, in (screen) pixels. It has no
+// effect when fullscreen. The game loop is notified with WindowResized.
+func SetWindowSize(width, height int32) {
+	internal.WindowSetSize(width, height)
+}
+
+// SetWindowMinSize sets the minimum size of the window, in (screen) pixels.
+func SetWindowMinSize(width, height int32) {
+	internal.WindowSetMinimumSize(width, height)
+}
+
+// WindowPosition returns the position of the window on the desktop.
+func WindowPosition() (x, y int32) {
+	return internal.WindowPosition()
+}
+
+// SetWindowPosition moves the window on the desktop.
+func SetWindowPosition(x, y int32) {
+	internal.WindowSetPosition(x, y)
+}
+
+// SetWindowIcon changes the icon of the window.
+func SetWindowIcon(img image.Image) error {
+	return internal.WindowSetIcon(img)
+}
+
+// SetBorderless removes (or restores) the window decorations.
+func SetBorderless(b bool) {
+	internal.WindowSetBorderless(b)
+}
+
+// SetAlwaysOnTop keeps the window above all others (or not).
+func SetAlwaysOnTop(t bool) {
+	internal.WindowSetAlwaysOnTop(t)
+}
+
+//------------------------------------------------------------------------------
+
+// IsFullscreen returns true if the window is currently fullscreen.
+func IsFullscreen() bool {
+	return internal.GetFullscreen()
+}
+
+// SetFullscreen switches the window to fullscreen, or back to windowed mode.
+// The choice is saved in the per-user settings file.
+func SetFullscreen(f bool) {
+	internal.SetFullscreen(f)
+}
+
+// ToggleFullscreen switches between fullscreen and windowed mode.
+func ToggleFullscreen() {
+	internal.ToggleFullscreen()
+}
+
+// SetFullscreenMode changes the kind of fullscreen, either "Desktop" (a
+// borderless window covering the display) or "Exclusive" (the display mode is
+// changed). It takes effect immediately if the window is fullscreen, and is
+// saved in the per-user settings file.
+func SetFullscreenMode(mode string) error {
+	return internal.SetFullscreenMode(mode)
+}
+
+// SetExclusiveMode changes the display mode used by exclusive fullscreen. The
+// mode should be one of those returned by Displays.
+func SetExclusiveMode(m DisplayMode) error {
+	return internal.SetExclusiveMode(m)
+}
+
+//------------------------------------------------------------------------------
+
+// DisplayInfo describes a monitor.
+type DisplayInfo = internal.Display
+
+// A DisplayMode is a resolution and refresh rate supported by a display.
+type DisplayMode = internal.DisplayMode
+
+// Displays returns the list of connected displays, with their DPI and
+// available fullscreen modes.
+func Displays() ([]DisplayInfo, error) {
+	return internal.Displays()
+}
+
+// WindowDisplay returns the index of the display containing the window.
+func WindowDisplay() int {
+	return internal.WindowDisplay()
+}
+
+//------------------------------------------------------------------------------
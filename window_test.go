@@ -0,0 +1,49 @@
+// Copyright (c) 2013-2017 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package carol_test
+
+import (
+	"testing"
+
+	"github.com/drakmaniso/carol"
+)
+
+//------------------------------------------------------------------------------
+
+type windowLoop struct {
+	carol.Handlers
+	width, height int32
+}
+
+func (l *windowLoop) Setup() error                      { return nil }
+func (l *windowLoop) Update() error                     { return nil }
+func (l *windowLoop) Draw(_, _ float64) error           { return nil }
+func (l *windowLoop) WindowResized(width, height int32) { l.width, l.height = width, height }
+
+//------------------------------------------------------------------------------
+
+func TestWindowResized(t *testing.T) {
+	var l windowLoop
+	s, err := carol.Simulate(&l, nil, false, carol.Window(640, 360))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if l.width != 640 || l.height != 360 {
+		t.Errorf("initial size %dx%d, expected 640x360", l.width, l.height)
+	}
+
+	s.Send(carol.Event{Kind: carol.EventWindowResized, X: 800, Y: 600})
+	err = s.Step(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, h := carol.WindowSize()
+	if l.width != 800 || l.height != 600 || w != 800 || h != 600 {
+		t.Errorf("resized to %dx%d (window %dx%d), expected 800x600", l.width, l.height, w, h)
+	}
+}
+
+//------------------------------------------------------------------------------
@@ -6,6 +6,7 @@ package gl
 //------------------------------------------------------------------------------
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"unsafe"
@@ -34,6 +35,10 @@ void DeleteBuffer(GLuint b) {
 	glDeleteBuffers(1, &b);
 }
 
+static inline void DeleteBufferTexture(GLuint t) {
+	glDeleteTextures(1, &t);
+}
+
 static inline void BufferSubData(GLuint buffer, GLintptr offset, GLsizei size, void *data) {
 	glNamedBufferSubData(buffer, offset, size, data);
 }
@@ -62,6 +67,10 @@ static inline void BindTextureUnit(GLuint unit, GLuint texture) {
 	glBindTextureUnit(unit, texture);
 }
 
+static inline void GetBufferSubData(GLuint buffer, GLintptr offset, GLsizei size, void *data) {
+	glGetNamedBufferSubData(buffer, offset, size, data);
+}
+
 */
 import "C"
 
@@ -394,8 +403,52 @@ func (bt BufferTexture) Bind(index uint32) {
 
 // Delete frees the buffer.
 func (tb *BufferTexture) Delete() {
+	C.DeleteBufferTexture(tb.texture)
 	C.DeleteBuffer(C.GLuint(tb.object))
-	//TODO: delete texture
+}
+
+//------------------------------------------------------------------------------
+
+// A PixelBuffer is a block of memory owned by the GPU, used to read pixels
+// without stalling the pipeline: the transfer started by
+// Framebuffer.ReadPixelsTo only completes when GetData is called, which should
+// be done at least one frame later.
+type PixelBuffer struct {
+	object C.GLuint
+	size   uintptr
+}
+
+// NewPixelBuffer asks the GPU to allocate a new block of memory, of size bytes.
+func NewPixelBuffer(size uintptr, f BufferFlags) PixelBuffer {
+	var pb PixelBuffer
+	pb.object = C.NewBuffer(C.GLsizeiptr(size), nil, C.GLbitfield(f))
+	pb.size = size
+	return pb
+}
+
+// Size returns the size of the buffer, in bytes.
+func (pb *PixelBuffer) Size() uintptr {
+	return pb.size
+}
+
+// GetData copies the beginning of the buffer into dst, waiting for the
+// transfer to complete if necessary.
+func (pb *PixelBuffer) GetData(dst []uint8) {
+	if uintptr(len(dst)) > pb.size {
+		setErr("in PixelBuffer GetData", errors.New("destination larger than the buffer"))
+		return
+	}
+	if len(dst) == 0 {
+		return
+	}
+	C.GetBufferSubData(pb.object, 0, C.GLsizei(len(dst)), unsafe.Pointer(&dst[0]))
+}
+
+// Delete frees the buffer.
+func (pb *PixelBuffer) Delete() {
+	C.DeleteBuffer(C.GLuint(pb.object))
+	pb.object = 0
+	pb.size = 0
 }
 
 //------------------------------------------------------------------------------
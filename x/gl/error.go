@@ -5,6 +5,20 @@ package gl
 
 //------------------------------------------------------------------------------
 
+import (
+	"errors"
+
+	"github.com/drakmaniso/carol/internal"
+)
+
+//------------------------------------------------------------------------------
+
+// ErrShaderExtension is reported by Shader when the file extension doesn't
+// correspond to any kind of shader.
+var ErrShaderExtension = errors.New("unknown shader file extension")
+
+//------------------------------------------------------------------------------
+
 // makeError returns nil if err is nil, or a wrapped error otherwise.
 func makeError(context string, err error) error {
 	if err == nil {
@@ -22,22 +36,24 @@ func (e wrappedError) Error() string {
 	return e.Context + ": " + e.Err.Error()
 }
 
+func (e wrappedError) Unwrap() error {
+	return e.Err
+}
+
 //------------------------------------------------------------------------------
 
-// Err returns the first OpenGL error since the previous call to Err().
+// Err returns the first OpenGL error since the previous call to Err(). If
+// Config.CollectErrors is set, it returns all errors since the previous call,
+// as an internal.ErrorList.
 func Err() error {
-	err := stickyErr
-	stickyErr = nil
-	return err
+	return stickyErr.Err()
 }
 
 func setErr(context string, err error) {
-	if stickyErr == nil {
-		stickyErr = makeError(context, err)
-	}
+	stickyErr.Set(makeError(context, err))
 	debug.Printf("gfx error: %s", makeError(context, err))
 }
 
-var stickyErr error
+var stickyErr internal.StickyError
 
 //------------------------------------------------------------------------------
@@ -5,6 +5,13 @@ package gl
 
 //------------------------------------------------------------------------------
 
+import (
+	"errors"
+	"unsafe"
+)
+
+//------------------------------------------------------------------------------
+
 /*
 #include "glad.h"
 
@@ -26,6 +33,22 @@ static inline void FramebufferBind(GLuint fbo, GLenum t) {
 	glBindFramebuffer(t, fbo);
 }
 
+static inline void FramebufferReadPixels(GLuint fbo, GLenum a, GLint x, GLint y, GLsizei w, GLsizei h, void *p) {
+	glNamedFramebufferReadBuffer(fbo, a);
+	glBindFramebuffer(GL_READ_FRAMEBUFFER, fbo);
+	glPixelStorei(GL_PACK_ALIGNMENT, 1);
+	glReadPixels(x, y, w, h, GL_RGBA, GL_UNSIGNED_BYTE, p);
+}
+
+static inline void FramebufferReadPixelsTo(GLuint fbo, GLenum a, GLint x, GLint y, GLsizei w, GLsizei h, GLuint buffer) {
+	glNamedFramebufferReadBuffer(fbo, a);
+	glBindFramebuffer(GL_READ_FRAMEBUFFER, fbo);
+	glPixelStorei(GL_PACK_ALIGNMENT, 1);
+	glBindBuffer(GL_PIXEL_PACK_BUFFER, buffer);
+	glReadPixels(x, y, w, h, GL_RGBA, GL_UNSIGNED_BYTE, 0);
+	glBindBuffer(GL_PIXEL_PACK_BUFFER, 0);
+}
+
 static inline void FramebufferBlit(GLuint fbo, GLuint dstFbo, GLint srcX1, GLint srcY1, GLint srcX2, GLint srcY2, GLint dstX1, GLint dstY1, GLint dstX2, GLint dstY2, GLbitfield m, GLenum f) {
 	glBlitNamedFramebuffer(fbo, dstFbo, srcX1, srcY1, srcX2, srcY2, dstX1, dstY1, dstX2, dstY2, m, f);
 }
@@ -67,6 +90,10 @@ const (
 	DepthAttachment        FramebufferAttachment = C.GL_DEPTH_ATTACHMENT
 	StencilAttachment      FramebufferAttachment = C.GL_STENCIL_ATTACHMENT
 	DepthStencilAttachment FramebufferAttachment = C.GL_DEPTH_STENCIL_ATTACHMENT
+
+	// Only for the default framebuffer
+	FrontBuffer FramebufferAttachment = C.GL_FRONT
+	BackBuffer  FramebufferAttachment = C.GL_BACK
 )
 
 //------------------------------------------------------------------------------
@@ -111,3 +138,36 @@ const (
 )
 
 //------------------------------------------------------------------------------
+
+// ReadPixels copies a rectangle of the framebuffer into dst, as 8-bit RGBA
+// values. The rows are stored from bottom to top.
+func (fb Framebuffer) ReadPixels(a FramebufferAttachment, x, y, width, height int32, dst []uint8) {
+	if len(dst) < int(4*width*height) {
+		setErr("in ReadPixels", errors.New("destination too small"))
+		return
+	}
+	C.FramebufferReadPixels(
+		fb.object,
+		C.GLenum(a),
+		C.GLint(x), C.GLint(y), C.GLsizei(width), C.GLsizei(height),
+		unsafe.Pointer(&dst[0]),
+	)
+}
+
+// ReadPixelsTo starts copying a rectangle of the framebuffer into the pixel
+// buffer dst, in the same format as ReadPixels. The call does not wait for the
+// transfer to complete.
+func (fb Framebuffer) ReadPixelsTo(a FramebufferAttachment, x, y, width, height int32, dst PixelBuffer) {
+	if dst.size < uintptr(4*width*height) {
+		setErr("in ReadPixelsTo", errors.New("destination too small"))
+		return
+	}
+	C.FramebufferReadPixelsTo(
+		fb.object,
+		C.GLenum(a),
+		C.GLint(x), C.GLint(y), C.GLsizei(width), C.GLsizei(height),
+		dst.object,
+	)
+}
+
+//------------------------------------------------------------------------------
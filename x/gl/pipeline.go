@@ -6,7 +6,6 @@ package gl
 //------------------------------------------------------------------------------
 
 import (
-	"errors"
 	"unsafe"
 )
 
@@ -256,7 +255,7 @@ func NewPipeline(o ...PipelineConfig) *Pipeline {
 	C.PipelineLinkProgram(p.object)
 	if errm := C.PipelineLinkError(p.object); errm != nil {
 		defer C.free(unsafe.Pointer(errm))
-		setErr("linking shaders", errors.New(C.GoString(errm)))
+		setErr("linking shaders", newShaderError("link", C.GoString(errm)))
 	}
 	// A bit inelegant, but makes the API easier
 	if oObj != p.object {
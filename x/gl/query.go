@@ -0,0 +1,74 @@
+// Copyright (c) 2013-2016 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package gl
+
+//------------------------------------------------------------------------------
+
+/*
+#include "glad.h"
+
+static inline GLuint NewQuery() {
+	GLuint q;
+	glGenQueries(1, &q);
+	return q;
+}
+
+static inline void QueryTimestamp(GLuint q) {
+	glQueryCounter(q, GL_TIMESTAMP);
+}
+
+static inline GLboolean QueryAvailable(GLuint q) {
+	GLuint64 a = 0;
+	glGetQueryObjectui64v(q, GL_QUERY_RESULT_AVAILABLE, &a);
+	return a != 0;
+}
+
+static inline GLuint64 QueryResult(GLuint q) {
+	GLuint64 r = 0;
+	glGetQueryObjectui64v(q, GL_QUERY_RESULT, &r);
+	return r;
+}
+
+static inline void DeleteQuery(GLuint q) {
+	glDeleteQueries(1, &q);
+}
+*/
+import "C"
+
+//------------------------------------------------------------------------------
+
+// A TimerQuery records the GPU time at which all previous commands have been
+// completed. The result is available asynchronously, usually a few frames
+// later.
+type TimerQuery struct {
+	object C.GLuint
+}
+
+// NewTimerQuery returns a new timer query.
+func NewTimerQuery() TimerQuery {
+	return TimerQuery{object: C.NewQuery()}
+}
+
+// Timestamp places the query in the command stream.
+func (q TimerQuery) Timestamp() {
+	C.QueryTimestamp(q.object)
+}
+
+// IsAvailable returns true if the result of the query is available.
+func (q TimerQuery) IsAvailable() bool {
+	return C.QueryAvailable(q.object) == C.GL_TRUE
+}
+
+// Result returns the GPU time recorded by the query, in nanoseconds. It waits
+// until the result is available.
+func (q TimerQuery) Result() uint64 {
+	return uint64(C.QueryResult(q.object))
+}
+
+// Delete frees the query.
+func (q TimerQuery) Delete() {
+	C.DeleteQuery(q.object)
+}
+
+//------------------------------------------------------------------------------
@@ -43,7 +43,8 @@ import "C"
 
 //------------------------------------------------------------------------------
 
-type logger interface {
+// A Logger receives the OpenGL debug messages.
+type Logger interface {
 	Print(v ...interface{})
 	Println(v ...interface{})
 	Printf(format string, v ...interface{})
@@ -55,7 +56,13 @@ func (nolog) Print(v ...interface{})                 {}
 func (nolog) Println(v ...interface{})               {}
 func (nolog) Printf(format string, v ...interface{}) {}
 
-var debug logger = nolog{}
+var debug Logger = nolog{}
+
+// SetLogger changes the destination of the OpenGL debug messages (which are
+// only enabled when Setup is called in debug mode).
+func SetLogger(l Logger) {
+	debug = l
+}
 
 //------------------------------------------------------------------------------
 
@@ -6,7 +6,6 @@ package gl
 //------------------------------------------------------------------------------
 
 import (
-	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -91,7 +90,7 @@ func Shader(path string) PipelineConfig {
 	case strings.HasSuffix(path, ".comp"):
 		return ComputeShader(f)
 	}
-	setErr("opening shader file", errors.New("unkown shader file extension"))
+	setErr(`opening shader file "`+path+`"`, ErrShaderExtension)
 	return func(*Pipeline) {}
 }
 
@@ -184,7 +183,7 @@ func ComputeShader(r io.Reader) PipelineConfig {
 func newShader(t uint32, r io.Reader) (C.GLuint, error) {
 	b, err := ioutil.ReadAll(r)
 	if err != nil {
-		return 0, fmt.Errorf("failed to read shader: %s", err)
+		return 0, fmt.Errorf("failed to read shader: %w", err)
 	}
 	cb := C.CString(string(b))
 	defer C.free(unsafe.Pointer(cb))
@@ -192,12 +191,30 @@ func newShader(t uint32, r io.Reader) (C.GLuint, error) {
 	s := C.CompileShader(C.GLenum(t), (*C.GLchar)(unsafe.Pointer(cb)))
 	if errm := C.ShaderCompileError(s); errm != nil {
 		defer C.free(unsafe.Pointer(errm))
-		return 0, errors.New(C.GoString(errm))
+		return 0, newShaderError(shaderStage(t), C.GoString(errm))
 	}
 
 	return s, nil
 }
 
+func shaderStage(t uint32) string {
+	switch t {
+	case C.GL_VERTEX_SHADER:
+		return "vertex"
+	case C.GL_FRAGMENT_SHADER:
+		return "fragment"
+	case C.GL_GEOMETRY_SHADER:
+		return "geometry"
+	case C.GL_TESS_CONTROL_SHADER:
+		return "tesselation control"
+	case C.GL_TESS_EVALUATION_SHADER:
+		return "tesselation evaluation"
+	case C.GL_COMPUTE_SHADER:
+		return "compute"
+	}
+	return "unknown"
+}
+
 //------------------------------------------------------------------------------
 
 // BindVertexSubroutines binds the vertex shader subroutines to indices in s.
@@ -0,0 +1,85 @@
+// Copyright (c) 2013-2016 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package gl
+
+//------------------------------------------------------------------------------
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+
+// A ShaderError is reported when a shader fails to compile, or a pipeline
+// fails to link. It can be retrieved from Err with errors.As.
+type ShaderError struct {
+	Stage    string // "vertex", "fragment", ..., or "link"
+	Log      string // the info log of the driver
+	Messages []ShaderMessage
+}
+
+// A ShaderMessage is a line of the info log. Line is 0 when the driver doesn't
+// give a line number.
+type ShaderMessage struct {
+	Line int
+	Text string
+}
+
+func (e *ShaderError) Error() string {
+	s := e.Stage + " shader error"
+	if e.Stage == "link" {
+		s = "link error"
+	}
+	if len(e.Messages) == 0 {
+		return s
+	}
+	for i, m := range e.Messages {
+		if i == 0 {
+			s += ": "
+		} else {
+			s += "; "
+		}
+		if m.Line > 0 {
+			s += "line " + strconv.Itoa(m.Line) + ": "
+		}
+		s += m.Text
+	}
+	return s
+}
+
+//------------------------------------------------------------------------------
+
+// Formats used by the drivers for line numbers.
+var (
+	nvidiaLogLine = regexp.MustCompile(`^\d+\((\d+)\)\s*:\s*(.*)$`)                 // 0(12) : error C0000: ...
+	mesaLogLine   = regexp.MustCompile(`^\d+:(\d+)(?:\(\d+\))?\s*:\s*(.*)$`)        // 0:12(5): error: ...
+	amdLogLine    = regexp.MustCompile(`^(ERROR|WARNING):\s*\d+:(\d+)\s*:\s*(.*)$`) // ERROR: 0:12: ...
+)
+
+func newShaderError(stage, log string) *ShaderError {
+	e := ShaderError{Stage: stage, Log: log}
+	for _, l := range strings.Split(log, "\n") {
+		l = strings.TrimSpace(strings.TrimRight(l, "\x00"))
+		if l == "" {
+			continue
+		}
+		m := ShaderMessage{Text: l}
+		if s := nvidiaLogLine.FindStringSubmatch(l); s != nil {
+			m.Line, _ = strconv.Atoi(s[1])
+			m.Text = s[2]
+		} else if s := mesaLogLine.FindStringSubmatch(l); s != nil {
+			m.Line, _ = strconv.Atoi(s[1])
+			m.Text = s[2]
+		} else if s := amdLogLine.FindStringSubmatch(l); s != nil {
+			m.Line, _ = strconv.Atoi(s[2])
+			m.Text = strings.ToLower(s[1]) + ": " + s[3]
+		}
+		e.Messages = append(e.Messages, m)
+	}
+	return &e
+}
+
+//------------------------------------------------------------------------------
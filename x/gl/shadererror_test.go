@@ -0,0 +1,64 @@
+// Copyright (c) 2013-2016 Laurent Moussault. All rights reserved.
+// Licensed under a simplified BSD license (see LICENSE file).
+
+package gl
+
+import (
+	"reflect"
+	"testing"
+)
+
+//------------------------------------------------------------------------------
+
+func TestShaderError(t *testing.T) {
+	tests := []struct {
+		driver string
+		log    string
+		want   []ShaderMessage
+	}{
+		{
+			"NVIDIA",
+			"0(12) : error C1008: undefined variable \"foo\"\n0(15) : warning C7555: unused\n\x00",
+			[]ShaderMessage{
+				{12, "error C1008: undefined variable \"foo\""},
+				{15, "warning C7555: unused"},
+			},
+		},
+		{
+			"Mesa",
+			"0:7(12): error: `foo' undeclared\n0:9: warning: unused\n",
+			[]ShaderMessage{
+				{7, "error: `foo' undeclared"},
+				{9, "warning: unused"},
+			},
+		},
+		{
+			"AMD",
+			"ERROR: 0:3: 'foo' : undeclared identifier \nWARNING: 0:42: extension not supported\nERROR: 1 compilation errors.  No code generated.\n",
+			[]ShaderMessage{
+				{3, "error: 'foo' : undeclared identifier"},
+				{42, "warning: extension not supported"},
+				{0, "ERROR: 1 compilation errors.  No code generated."},
+			},
+		},
+		{
+			"unknown",
+			"error: main() missing\n",
+			[]ShaderMessage{
+				{0, "error: main() missing"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		e := newShaderError("fragment", tt.log)
+		if e.Stage != "fragment" || e.Log != tt.log {
+			t.Errorf("%s: got stage %q and log %q", tt.driver, e.Stage, e.Log)
+		}
+		if !reflect.DeepEqual(e.Messages, tt.want) {
+			t.Errorf("%s: got %#v, expected %#v", tt.driver, e.Messages, tt.want)
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
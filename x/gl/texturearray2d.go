@@ -45,6 +45,10 @@ static inline void BindTextureUnit(GLuint unit, GLuint texture) {
 	glBindTextureUnit(unit, texture);
 }
 
+static inline void DeleteTextureArray2D(GLuint texture) {
+	glDeleteTextures(1, &texture);
+}
+
 */
 import "C"
 
@@ -88,4 +92,10 @@ func (t *TextureArray2D) Bind(index uint32) {
 	C.BindTextureUnit(C.GLuint(index), t.object)
 }
 
+// Delete frees the texture.
+func (t *TextureArray2D) Delete() {
+	C.DeleteTextureArray2D(t.object)
+	t.object = 0
+}
+
 //------------------------------------------------------------------------------